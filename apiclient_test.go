@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/client"
+)
+
+const testAPIKey = "s3cr3t"
+
+// newAPIServer starts a server with the router of main on an empty in-memory repository. Authentication is enabled
+// with testAPIKey, and the responses are validated against the OpenAPI document.
+func newAPIServer(t *testing.T) (*httptest.Server, *memoryRepository) {
+	t.Helper()
+	cfg := defaultConfig()
+	cfg.AuthEnabled, cfg.APIKeys = true, []string{"ci:editor:" + testAPIKey}
+	cfg.OpenAPIValidation = "response"
+	repo := newMemoryRepository()
+	m := MusicDbOperation{repo: repo, cfg: cfg, state: &serverState{}, tenants: newTenantRouter(cfg, zerolog.Nop())}
+	authn, err := newAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := m.newRouter(authn, newRateLimiter(cfg), zerolog.Nop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, repo
+}
+
+func newAPIClient(t *testing.T, server *httptest.Server, opts ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.New(server.URL, append([]client.Option{client.WithAPIKey(testAPIKey)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClient(t *testing.T) {
+	server, repo := newAPIServer(t)
+	c := newAPIClient(t, server)
+	ctx := context.Background()
+
+	ids, err := c.RegisterSingerWithAlbum(ctx, client.SingerAlbumInfo{FirstName: "Alice", LastName: "Smith", AlbumName: "Songs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	albums, err := c.AlbumsOfSinger(ctx, ids.SingerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(albums) != 1 || albums[0].ID != ids.AlbumId || albums[0].Title != "Songs" || albums[0].SingerId != ids.SingerId ||
+		albums[0].Singer.FirstName.String != "Alice" || len(albums[0].Tracks) == 0 {
+		t.Errorf("got albums %+v", albums)
+	}
+
+	_, err = c.AlbumsOfSinger(ctx, "unknown")
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Message != "user not found" {
+		t.Errorf("got error %v, want the not found error of the server", err)
+	}
+	if _, err := newAPIClient(t, server, client.WithAPIKey("")).AlbumsOfSinger(ctx, ids.SingerId); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("got error %v, want ErrUnauthorized", err)
+	}
+
+	// The server executes requests with the same idempotency key once.
+	keyCtx := client.WithIdempotencyKey(ctx, uuid.NewString())
+	info := client.SingerAlbumInfo{FirstName: "Bob", LastName: "Jones", AlbumName: "Tunes"}
+	first, err := c.RegisterSingerWithAlbum(keyCtx, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.RegisterSingerWithAlbum(keyCtx, info)
+	if err != nil || second != first {
+		t.Errorf("got %+v, %v for the second request, want %+v", second, err, first)
+	}
+	singers, err := repo.ListSingers(ctx, Page{Limit: 10})
+	if err != nil || len(singers) != 2 {
+		t.Errorf("got %d singers, %v, want 2", len(singers), err)
+	}
+	info.AlbumName = "Other"
+	if _, err := c.RegisterSingerWithAlbum(keyCtx, info); !errors.Is(err, client.ErrIdempotencyKeyReused) {
+		t.Errorf("got error %v, want ErrIdempotencyKeyReused", err)
+	}
+}
+
+func TestClientConcerts(t *testing.T) {
+	server, repo := newAPIServer(t)
+	ctx := context.Background()
+	venue := &Venue{BaseModel: BaseModel{ID: uuid.NewString()}, Name: "Hall"}
+	singer := newSinger("Alice", "Smith")
+	if err := repo.CreateVenue(ctx, venue); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateSinger(ctx, singer); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, time.March, 1, 20, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		concert := &Concert{BaseModel: BaseModel{ID: uuid.NewString()}, Name: fmt.Sprintf("Concert %d", i),
+			VenueId: venue.ID, SingerId: singer.ID, StartTime: start.AddDate(0, 0, i), EndTime: start.AddDate(0, 0, i).Add(2 * time.Hour)}
+		if err := repo.CreateConcert(ctx, concert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newAPIClient(t, server)
+	for _, tc := range []struct {
+		query client.ConcertQuery
+		want  []string
+	}{
+		{client.ConcertQuery{PageSize: 2}, []string{"Concert 0", "Concert 1", "Concert 2", "Concert 3", "Concert 4"}},
+		{client.ConcertQuery{PageSize: 5}, []string{"Concert 0", "Concert 1", "Concert 2", "Concert 3", "Concert 4"}},
+		{client.ConcertQuery{From: start.AddDate(0, 0, 3), VenueID: venue.ID}, []string{"Concert 3", "Concert 4"}},
+		{client.ConcertQuery{SingerID: "unknown"}, nil},
+	} {
+		var names []string
+		it := c.Concerts(ctx, tc.query)
+		for it.Next() {
+			concert := it.Concert()
+			if concert.Venue.Name != "Hall" || concert.Singer.LastName != "Smith" {
+				t.Errorf("got concert %+v without its venue and singer", concert)
+			}
+			names = append(names, concert.Name)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tc.want) {
+			t.Errorf("%+v: got concerts %v, want %v", tc.query, names, tc.want)
+		}
+	}
+
+	it := c.Concerts(ctx, client.ConcertQuery{PageSize: maxConcertLimit + 1})
+	if it.Next() || !errors.Is(it.Err(), client.ErrBadRequest) {
+		t.Errorf("got error %v, want ErrBadRequest", it.Err())
+	}
+}
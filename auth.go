@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/golang-jwt/jwt/v4"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Role is the role of a caller. Each role includes the permissions of the roles before it.
+type Role string
+
+const (
+	roleReader Role = "reader"
+	roleEditor Role = "editor"
+	roleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{roleReader: 1, roleEditor: 2, roleAdmin: 3}
+
+// allows returns true if a caller with role r may call routes that require the role required.
+func (r Role) allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	Subject string
+	Role    Role
+	// Method is the authentication method: api_key, jwt, or none if authentication is disabled.
+	Method string
+}
+
+type identityKey struct{}
+
+func withIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// identityFrom returns the caller of the request of ctx, or nil if the request has not been authenticated.
+func identityFrom(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// credentials are the credentials that a client sent with a request, from the HTTP headers or the gRPC metadata.
+type credentials struct {
+	apiKey      string
+	bearerToken string
+}
+
+func (c credentials) empty() bool {
+	return c.apiKey == "" && c.bearerToken == ""
+}
+
+// errNoCredentials is returned by an Authenticator that does not handle the credentials of a request.
+var errNoCredentials = errors.New("no credentials")
+
+// Authenticator verifies the credentials of a request.
+type Authenticator interface {
+	// Authenticate returns the identity of the caller, or errNoCredentials if the credentials are not of a kind
+	// that the authenticator handles.
+	Authenticate(creds credentials) (*Identity, error)
+}
+
+// apiKeyAuthenticator authenticates static API keys that are sent in the X-API-Key header.
+type apiKeyAuthenticator struct {
+	keys []apiKey
+}
+
+type apiKey struct {
+	key      []byte
+	identity *Identity
+}
+
+// newAPIKeyAuthenticator parses API keys in the format subject:role:key.
+func newAPIKeyAuthenticator(specs []string) (*apiKeyAuthenticator, error) {
+	a := &apiKeyAuthenticator{}
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, errors.New("API keys must have the format subject:role:key")
+		}
+		role := Role(parts[1])
+		if _, ok := roleRanks[role]; !ok {
+			return nil, fmt.Errorf("API key of %s has unknown role %q", parts[0], parts[1])
+		}
+		a.keys = append(a.keys, apiKey{
+			key:      []byte(parts[2]),
+			identity: &Identity{Subject: parts[0], Role: role, Method: "api_key"},
+		})
+	}
+	return a, nil
+}
+
+func (a *apiKeyAuthenticator) Authenticate(creds credentials) (*Identity, error) {
+	if creds.apiKey == "" {
+		return nil, errNoCredentials
+	}
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(k.key, []byte(creds.apiKey)) == 1 {
+			return k.identity, nil
+		}
+	}
+	return nil, errors.New("invalid API key")
+}
+
+// jwtAuthenticator authenticates JWTs that are sent as bearer tokens. Tokens are signed with HS256 and a shared
+// secret, or with RS256 and one of the configured public keys. The role of the caller is taken from the role claim.
+type jwtAuthenticator struct {
+	parser   *jwt.Parser
+	secret   []byte
+	keys     map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+}
+
+type jwtClaims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// newJWTAuthenticator loads the RSA public keys from PEM files. The key ID (kid) of a key is the file name
+// without extension.
+func newJWTAuthenticator(cfg *Config) (*jwtAuthenticator, error) {
+	a := &jwtAuthenticator{
+		parser:   jwt.NewParser(jwt.WithValidMethods([]string{"HS256", "RS256"})),
+		secret:   []byte(cfg.JWTSecret),
+		keys:     map[string]*rsa.PublicKey{},
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+	}
+	for _, path := range cfg.JWTPublicKeyFiles {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read JWT public key: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT public key %s: %w", path, err)
+		}
+		a.keys[strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))] = key
+	}
+	return a, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(creds credentials) (*Identity, error) {
+	if creds.bearerToken == "" {
+		return nil, errNoCredentials
+	}
+	claims := &jwtClaims{}
+	if _, err := a.parser.ParseWithClaims(creds.bearerToken, claims, a.key); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return nil, errors.New("invalid token: wrong issuer")
+	}
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return nil, errors.New("invalid token: wrong audience")
+	}
+	if _, ok := roleRanks[claims.Role]; !ok {
+		return nil, fmt.Errorf("invalid token: unknown role %q", claims.Role)
+	}
+	return &Identity{Subject: claims.Subject, Role: claims.Role, Method: "jwt"}, nil
+}
+
+// key returns the key that verifies the signature of token.
+func (a *jwtAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(a.secret) == 0 {
+			return nil, errors.New("HS256 is not configured")
+		}
+		return a.secret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := a.keys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(a.keys) == 1 {
+			for _, key := range a.keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+}
+
+// auth authenticates the callers of the HTTP and gRPC APIs. If authentication is disabled, every caller is an
+// anonymous editor, so that the API can be used without credentials, but the /admin routes cannot.
+type auth struct {
+	enabled        bool
+	authenticators []Authenticator
+}
+
+var anonymous = &Identity{Subject: "anonymous", Role: roleEditor, Method: "none"}
+
+func newAuth(cfg *Config) (*auth, error) {
+	a := &auth{enabled: cfg.AuthEnabled}
+	if !a.enabled {
+		return a, nil
+	}
+	keys, err := newAPIKeyAuthenticator(cfg.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := newJWTAuthenticator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	a.authenticators = []Authenticator{keys, tokens}
+	return a, nil
+}
+
+// authenticate returns the identity of the caller. It returns nil without an error if there are no credentials,
+// so that public routes can be called anonymously.
+func (a *auth) authenticate(creds credentials) (*Identity, error) {
+	if !a.enabled {
+		return anonymous, nil
+	}
+	if creds.empty() {
+		return nil, nil
+	}
+	for _, authenticator := range a.authenticators {
+		id, err := authenticator.Authenticate(creds)
+		if errors.Is(err, errNoCredentials) {
+			continue
+		}
+		return id, err
+	}
+	return nil, errors.New("unsupported credentials")
+}
+
+// middleware authenticates the caller and stores the identity in the request context, the request log and the
+// trace. Requests with invalid credentials are rejected with 401. Whether the caller may call a route is checked
+// by requireRole.
+func (a *auth) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		creds := credentials{apiKey: r.Header.Get(apiKeyHeader)}
+		if header := r.Header.Get("Authorization"); len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+			creds.bearerToken = header[7:]
+		}
+		id, err := a.authenticate(creds)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			errorRender(w, r, http.StatusUnauthorized, err)
+			return
+		}
+		if id == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		httplog.LogEntrySetFields(r.Context(), map[string]interface{}{"user": id.Subject, "role": string(id.Role)})
+		trace.SpanFromContext(r.Context()).SetAttributes(semconv.EnduserIDKey.String(id.Subject), semconv.EnduserRoleKey.String(string(id.Role)))
+		next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), id)))
+	})
+}
+
+// requireRole rejects requests of anonymous callers with 401, and of callers without the role with 403.
+func requireRole(role Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := checkRole(r.Context(), role); err != nil {
+				code := http.StatusForbidden
+				if identityFrom(r.Context()) == nil {
+					w.Header().Set("WWW-Authenticate", "Bearer")
+					code = http.StatusUnauthorized
+				}
+				errorRender(w, r, code, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireReaderOrEditor requires the reader role for GET requests and the editor role for all other requests.
+// POST requests to /graphql only require the reader role, as the mutations check the editor role themselves.
+func requireReaderOrEditor(next http.Handler) http.Handler {
+	reader, editor := requireRole(roleReader)(next), requireRole(roleEditor)(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWrite(r) && r.URL.Path != "/graphql" {
+			editor.ServeHTTP(w, r)
+			return
+		}
+		reader.ServeHTTP(w, r)
+	})
+}
+
+// checkRole returns an error if the caller of the request of ctx does not have the role.
+func checkRole(ctx context.Context, role Role) error {
+	id := identityFrom(ctx)
+	if id == nil {
+		return errors.New("authentication required")
+	}
+	if !id.Role.allows(role) {
+		return fmt.Errorf("%s role required", role)
+	}
+	return nil
+}
+
+// grpcMethodRoles are the roles that the gRPC methods require. Methods that are not listed require the reader role.
+var grpcMethodRoles = map[string]Role{
+	"/music.v1.MusicService/RegisterSingerWithAlbum": roleEditor,
+}
+
+// unaryInterceptor authenticates gRPC calls with the x-api-key or authorization metadata and checks their role.
+func (a *auth) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var creds credentials
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(strings.ToLower(apiKeyHeader)); len(v) > 0 {
+			creds.apiKey = v[0]
+		}
+		if v := md.Get("authorization"); len(v) > 0 && len(v[0]) > 7 && strings.EqualFold(v[0][:7], "Bearer ") {
+			creds.bearerToken = v[0][7:]
+		}
+	}
+	id, err := a.authenticate(creds)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if id != nil {
+		ctx = withIdentity(ctx, id)
+	}
+	role, ok := grpcMethodRoles[info.FullMethod]
+	if !ok {
+		role = roleReader
+	}
+	if err := checkRole(ctx, role); err != nil {
+		if id == nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return handler(ctx, req)
+}
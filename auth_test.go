@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+)
+
+const testJWTSecret = "test-secret"
+
+func signedToken(t *testing.T, role Role, expiresAt time.Time) string {
+	t.Helper()
+	claims := jwtClaims{Role: role, RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "alice",
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthRequiresRole(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.AuthEnabled, cfg.JWTSecret = true, testJWTSecret
+	cfg.APIKeys = []string{"ci:reader:reader-key", "deploy:editor:editor:key"}
+	authn, err := newAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := authn.middleware(requireRole(roleEditor)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := identityFrom(r.Context()); id == nil || id.Subject == "" {
+			t.Errorf("got identity %+v in the handler", id)
+		}
+	})))
+
+	valid := signedToken(t, roleEditor, time.Now().Add(time.Hour))
+	for _, tc := range []struct {
+		name          string
+		apiKey, token string
+		want          int
+	}{
+		{"missing token", "", "", http.StatusUnauthorized},
+		{"expired token", "", signedToken(t, roleEditor, time.Now().Add(-time.Minute)), http.StatusUnauthorized},
+		{"token with another secret", "", valid[:len(valid)-2] + "xx", http.StatusUnauthorized},
+		{"token with unknown role", "", signedToken(t, "owner", time.Now().Add(time.Hour)), http.StatusUnauthorized},
+		{"token with wrong role", "", signedToken(t, roleReader, time.Now().Add(time.Hour)), http.StatusForbidden},
+		{"valid token", "", valid, http.StatusOK},
+		{"invalid API key", "editor-key", "", http.StatusUnauthorized},
+		{"API key with wrong role", "reader-key", "", http.StatusForbidden},
+		// The key of an API key spec may contain colons.
+		{"valid API key", "editor:key", "", http.StatusOK},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/api/register-singer-with-album", nil)
+		if tc.apiKey != "" {
+			r.Header.Set(apiKeyHeader, tc.apiKey)
+		}
+		if tc.token != "" {
+			r.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tc.want {
+			t.Errorf("%s: got status %d, want %d: %s", tc.name, w.Code, tc.want, w.Body)
+		}
+	}
+}
+
+func TestNewAPIKeyAuthenticator(t *testing.T) {
+	for _, tc := range []struct {
+		spec  string
+		valid bool
+	}{
+		{"ci:editor:key", true},
+		{"ci:admin:k:e:y", true},
+		{"ci:editor", false},
+		{":editor:key", false},
+		{"ci:editor:", false},
+		{"ci:owner:key", false},
+	} {
+		if _, err := newAPIKeyAuthenticator([]string{tc.spec}); (err == nil) != tc.valid {
+			t.Errorf("%q: got error %v, want valid %v", tc.spec, err, tc.valid)
+		}
+	}
+}
+
+func TestAdminRoutesRequireAuthentication(t *testing.T) {
+	server, _ := newAPIServer(t)
+	res, err := http.Get(server.URL + "/admin/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got status %d without credentials, want 401", res.StatusCode)
+	}
+
+	// Without authentication, callers are editors and the admin routes are not served.
+	cfg := defaultConfig()
+	authn, err := newAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := MusicDbOperation{repo: newMemoryRepository(), cfg: cfg, state: &serverState{}, tenants: newTenantRouter(cfg, zerolog.Nop())}
+	r, err := m.newRouter(authn, newRateLimiter(cfg), zerolog.Nop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/admin/faults", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s /admin/faults: got status %d without authentication, want 404", method, w.Code)
+		}
+	}
+	if anonymous.Role.allows(roleAdmin) {
+		t.Error("anonymous callers are admins")
+	}
+}
@@ -0,0 +1,309 @@
+// Package client is a Go client of the HTTP API of the music catalog. It shares the request and response types with
+// the server, and takes care of timeouts, retries, idempotency keys, the decoding of errors and pagination.
+//
+//	c, err := client.New("https://catalog.example.com", client.WithAPIKey(key))
+//	ids, err := c.RegisterSingerWithAlbum(ctx, client.SingerAlbumInfo{FirstName: "Alice", LastName: "Smith", AlbumName: "Songs"})
+//	albums, err := c.AlbumsOfSinger(ctx, ids.SingerId)
+//	if errors.Is(err, client.ErrNotFound) { ... }
+//
+// A Client is safe for concurrent use.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Client calls the API of one server.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	header      http.Header
+	timeout     time.Duration
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client that sends the requests, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAPIKey authenticates the requests with an API key.
+func WithAPIKey(key string) Option {
+	return WithHeader(APIKeyHeader, key)
+}
+
+// WithHeader adds a header to all requests, for example the tenant header to select a tenant.
+func WithHeader(name, value string) Option {
+	return func(c *Client) { c.header.Set(name, value) }
+}
+
+// WithTimeout limits the duration of each attempt of a request, 30s by default. Zero means no limit. The context of
+// a call limits the duration of all attempts together.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.timeout = timeout }
+}
+
+// WithRetries sets the maximum number of attempts of a request, 4 by default, and the delay before the first retry,
+// 100ms by default. The delay doubles with every retry. maxAttempts 1 disables retries.
+func WithRetries(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *Client) { c.maxAttempts, c.retryDelay = maxAttempts, baseDelay }
+}
+
+// maxRetryDelay limits the delay between attempts, unless the server asks for a longer delay with Retry-After.
+const maxRetryDelay = 5 * time.Second
+
+// New returns a client of the server at baseURL, like https://catalog.example.com.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	c := &Client{
+		baseURL:     baseURL,
+		httpClient:  http.DefaultClient,
+		header:      http.Header{},
+		timeout:     30 * time.Second,
+		maxAttempts: 4,
+		retryDelay:  100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxAttempts < 1 {
+		c.maxAttempts = 1
+	}
+	return c, nil
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context that makes the POST requests of a call use the given idempotency key. By
+// default, each call uses a new random key for all of its attempts. A caller that retries a call itself, for
+// example after a crash, passes the key of the first call so that the server executes it only once.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// RegisterSingerWithAlbum creates a singer and an album with random tracks. Retries are safe, as all attempts use the
+// same idempotency key.
+func (c *Client) RegisterSingerWithAlbum(ctx context.Context, info SingerAlbumInfo) (SingerAlbumIds, error) {
+	var ids SingerAlbumIds
+	err := c.do(ctx, http.MethodPost, "/api/register-singer-with-album", nil, info, &ids)
+	return ids, err
+}
+
+// AlbumsOfSinger returns the albums of a singer with the singer and the tracks of each album. It returns an error
+// that matches ErrNotFound if the singer has no albums.
+func (c *Client) AlbumsOfSinger(ctx context.Context, singerID string) ([]Album, error) {
+	var albums []Album
+	err := c.do(ctx, http.MethodGet, "/api/get-albums-of-singerid/"+url.PathEscape(singerID), nil, nil, &albums)
+	return albums, err
+}
+
+// Concerts returns an iterator over the concerts that match the query, ordered by start time. The pages are fetched
+// when the iterator reaches them, so concerts that are created or deleted in the meantime can be skipped or returned
+// twice.
+func (c *Client) Concerts(ctx context.Context, query ConcertQuery) *ConcertIterator {
+	return &ConcertIterator{c: c, ctx: ctx, query: query}
+}
+
+// ConcertIterator iterates over the concerts of a query:
+//
+//	it := c.Concerts(ctx, client.ConcertQuery{VenueID: id})
+//	for it.Next() {
+//		concert := it.Concert()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type ConcertIterator struct {
+	c      *Client
+	ctx    context.Context
+	query  ConcertQuery
+	offset int
+	page   []Concert
+	next   int
+	last   bool
+	err    error
+}
+
+// Next advances to the next concert and returns false when there are no more concerts or an error occurred.
+func (it *ConcertIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.next < len(it.page) {
+		it.next++
+		return true
+	}
+	if it.last {
+		return false
+	}
+	q := url.Values{}
+	if !it.query.From.IsZero() {
+		q.Set("from", it.query.From.Format(time.RFC3339))
+	}
+	if !it.query.To.IsZero() {
+		q.Set("to", it.query.To.Format(time.RFC3339))
+	}
+	if it.query.VenueID != "" {
+		q.Set("venue_id", it.query.VenueID)
+	}
+	if it.query.SingerID != "" {
+		q.Set("singer_id", it.query.SingerID)
+	}
+	pageSize := it.query.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	q.Set("limit", strconv.Itoa(pageSize))
+	q.Set("offset", strconv.Itoa(it.offset))
+	it.page, it.next = nil, 0
+	if it.err = it.c.do(it.ctx, http.MethodGet, "/api/concerts", q, nil, &it.page); it.err != nil {
+		return false
+	}
+	it.offset += len(it.page)
+	it.last = len(it.page) < pageSize
+	if len(it.page) == 0 {
+		return false
+	}
+	it.next = 1
+	return true
+}
+
+// Concert returns the current concert.
+func (it *ConcertIterator) Concert() Concert {
+	return it.page[it.next-1]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConcertIterator) Err() error {
+	return it.err
+}
+
+// do sends a request and decodes the JSON response into out. Requests are retried after transport errors and
+// responses that indicate a temporary failure, but not after responses that cannot be decoded. POST requests carry an idempotency key, so they are retried too.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	key := ""
+	if method == http.MethodPost {
+		key, _ = ctx.Value(idempotencyKey{}).(string)
+		if key == "" {
+			key = uuid.NewString()
+		}
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, u, data, key, out)
+		if err == nil || attempt == c.maxAttempts || !retryable(err, key != "") || ctx.Err() != nil {
+			return err
+		}
+		delay := c.retryDelay << (attempt - 1)
+		if delay < 0 || delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+		// Full jitter spreads the retries of clients that failed at the same time.
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends one request. It returns the delay that the server asked for with Retry-After.
+func (c *Client) attempt(ctx context.Context, method, u string, body []byte, key string, out interface{}) (time.Duration, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		e := decodeError(resp)
+		return e.RetryAfter, e
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("invalid response of %s %s: %w", method, req.URL.Path, err)
+	}
+	return 0, nil
+}
+
+// retryable reports whether a request that failed with err can be sent again. Conflicts are only retried for
+// idempotent requests, for which they mean that an earlier attempt is still in progress.
+func retryable(err error, idempotent bool) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// The request did not get a response.
+		return !errors.Is(err, context.Canceled)
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		// The response was invalid, which another attempt does not change.
+		return false
+	}
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return idempotent
+	}
+	return false
+}
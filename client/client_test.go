@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubServer responds to the nth request with responses[n], and repeats the last response for later requests.
+type stubServer struct {
+	mu        sync.Mutex
+	responses []func(w http.ResponseWriter)
+	requests  []*http.Request
+}
+
+func (s *stubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	n := len(s.requests)
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+	if n >= len(s.responses) {
+		n = len(s.responses) - 1
+	}
+	s.responses[n](w)
+}
+
+func respond(status int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func newStubClient(t *testing.T, stub *stubServer, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	c, err := New(server.URL+"/", append([]Option{WithRetries(4, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRetriesUseTheSameIdempotencyKey(t *testing.T) {
+	stub := &stubServer{responses: []func(http.ResponseWriter){
+		respond(http.StatusServiceUnavailable, `{"ERROR": "too many concurrent requests"}`),
+		respond(http.StatusConflict, `{"ERROR": "a request with the same idempotency key is in progress"}`),
+		respond(http.StatusOK, `{"singer_id": "s1", "album_id": "a1"}`),
+	}}
+	c := newStubClient(t, stub, WithAPIKey("key"))
+	ids, err := c.RegisterSingerWithAlbum(context.Background(), SingerAlbumInfo{LastName: "Smith"})
+	if err != nil || ids != (SingerAlbumIds{SingerId: "s1", AlbumId: "a1"}) {
+		t.Fatalf("got %+v, %v", ids, err)
+	}
+	if len(stub.requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(stub.requests))
+	}
+	key := stub.requests[0].Header.Get(IdempotencyKeyHeader)
+	for _, r := range stub.requests {
+		if r.Header.Get(IdempotencyKeyHeader) != key || key == "" || r.Header.Get(APIKeyHeader) != "key" {
+			t.Errorf("got headers %v, want idempotency key %q and the API key", r.Header, key)
+		}
+	}
+
+	// Each call has its own key, unless the caller sets one.
+	stub.requests, stub.responses = nil, stub.responses[2:]
+	c.RegisterSingerWithAlbum(context.Background(), SingerAlbumInfo{})
+	c.RegisterSingerWithAlbum(WithIdempotencyKey(context.Background(), "k1"), SingerAlbumInfo{})
+	if got := stub.requests[0].Header.Get(IdempotencyKeyHeader); got == key || got == "" {
+		t.Errorf("got idempotency key %q for a new call", got)
+	}
+	if got := stub.requests[1].Header.Get(IdempotencyKeyHeader); got != "k1" {
+		t.Errorf("got idempotency key %q, want k1", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		response func(http.ResponseWriter)
+		attempts int
+		want     error
+		message  string
+	}{
+		{"error response", respond(http.StatusNotFound, `{"ERROR": "user not found"}`), 1, ErrNotFound, "user not found"},
+		{"conflict of a GET request", respond(http.StatusConflict, `{"ERROR": "conflict"}`), 1, ErrConflict, "conflict"},
+		{"rate limited", respond(http.StatusTooManyRequests, `{"ERROR": "rate limit exceeded"}`), 4, ErrRateLimited, "rate limit exceeded"},
+		{"response of a proxy", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("upstream connect error\n"))
+		}, 4, ErrUnavailable, "upstream connect error"},
+		{"empty response", respond(http.StatusInternalServerError, ""), 1, ErrInternal, "Internal Server Error"},
+	} {
+		stub := &stubServer{responses: []func(http.ResponseWriter){tc.response}}
+		_, err := newStubClient(t, stub).AlbumsOfSinger(context.Background(), "s1")
+		var e *Error
+		if !errors.Is(err, tc.want) || !errors.As(err, &e) || e.Message != tc.message {
+			t.Errorf("%s: got error %v, want %v with message %q", tc.name, err, tc.want, tc.message)
+		}
+		if len(stub.requests) != tc.attempts {
+			t.Errorf("%s: got %d attempts, want %d", tc.name, len(stub.requests), tc.attempts)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	stub := &stubServer{responses: []func(http.ResponseWriter){
+		func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "1")
+			respond(http.StatusTooManyRequests, `{"ERROR": "rate limit exceeded"}`)(w)
+		},
+		respond(http.StatusOK, `[]`),
+	}}
+	c := newStubClient(t, stub)
+	start := time.Now()
+	if _, err := c.AlbumsOfSinger(context.Background(), "s1"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < time.Second {
+		t.Errorf("retried after %v, want the Retry-After delay of 1s", d)
+	}
+
+	// The context limits the time of all attempts.
+	stub.requests = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	stub.responses = stub.responses[:1]
+	if _, err := c.AlbumsOfSinger(ctx, "s1"); !errors.Is(err, ErrRateLimited) || len(stub.requests) != 1 {
+		t.Errorf("got error %v after %d attempts, want ErrRateLimited after 1 attempt", err, len(stub.requests))
+	}
+}
+
+func TestTimeoutOfAttempts(t *testing.T) {
+	stub := &stubServer{responses: []func(http.ResponseWriter){
+		func(w http.ResponseWriter) { time.Sleep(200 * time.Millisecond) },
+		respond(http.StatusOK, `[{"ID": "a1", "Title": "Songs"}]`),
+	}}
+	c := newStubClient(t, stub, WithTimeout(50*time.Millisecond))
+	albums, err := c.AlbumsOfSinger(context.Background(), "s 1")
+	if err != nil || len(albums) != 1 || albums[0].Title != "Songs" {
+		t.Fatalf("got %+v, %v", albums, err)
+	}
+	if path := stub.requests[1].URL.EscapedPath(); path != "/api/get-albums-of-singerid/s%201" {
+		t.Errorf("got path %s", path)
+	}
+}
+
+func TestInvalidResponsesAreNotRetried(t *testing.T) {
+	stub := &stubServer{responses: []func(http.ResponseWriter){respond(http.StatusOK, `{"ID": `)}}
+	_, err := newStubClient(t, stub).AlbumsOfSinger(context.Background(), "s1")
+	var e *Error
+	if err == nil || errors.As(err, &e) || len(stub.requests) != 1 {
+		t.Errorf("got error %v after %d attempts, want a decode error after 1 attempt", err, len(stub.requests))
+	}
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors that an *Error matches with errors.Is, depending on its status code.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	// ErrNotFound is also returned for unknown tenants.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means that a request with the same idempotency key is still in progress.
+	ErrConflict = errors.New("conflict")
+	// ErrIdempotencyKeyReused means that the idempotency key was used before for a different request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused")
+	ErrRateLimited          = errors.New("rate limited")
+	// ErrUnavailable is returned for 502, 503 and 504, for example if the server is overloaded or shutting down.
+	ErrUnavailable = errors.New("unavailable")
+	ErrInternal    = errors.New("internal server error")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusUnprocessableEntity: ErrIdempotencyKeyReused,
+	http.StatusTooManyRequests:     ErrRateLimited,
+	http.StatusInternalServerError: ErrInternal,
+	http.StatusBadGateway:          ErrUnavailable,
+	http.StatusServiceUnavailable:  ErrUnavailable,
+	http.StatusGatewayTimeout:      ErrUnavailable,
+}
+
+// Error is returned for responses with an error status. It is the last response if the request was retried.
+type Error struct {
+	StatusCode int
+	// Message is the error message of the server.
+	Message string
+	// RetryAfter is the delay that the server asked for with the Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("catalog API: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether target is the error of the status code, like ErrNotFound for 404.
+func (e *Error) Is(target error) bool {
+	err, ok := statusErrors[e.StatusCode]
+	return ok && err == target
+}
+
+// decodeError reads an error response. Responses that are not an ErrorResponse, for example of a proxy, keep their
+// body as the message.
+func decodeError(resp *http.Response) *Error {
+	e := &Error{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var errResp ErrorResponse
+	if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+		e.Message = errResp.Error
+	} else if e.Message = strings.TrimSpace(string(data)); e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
+
+// parseRetryAfter parses a Retry-After header with a delay in seconds or a date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"time"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/model"
+)
+
+// Headers of the catalog API.
+const (
+	// APIKeyHeader carries the API key if the server requires authentication.
+	APIKeyHeader = "X-API-Key"
+	// IdempotencyKeyHeader makes a POST request idempotent: the server executes the first request with a key, and
+	// returns the response of that request for later requests with the same key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to true on responses that the server returned for an earlier request with the
+	// same idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// The request and response bodies of the API are the types of the model package, which the server encodes.
+type (
+	SingerAlbumInfo = model.SingerAlbumInfo
+	SingerAlbumIds  = model.SingerAlbumIds
+	ErrorResponse   = model.ErrorResponse
+	Singer          = model.Singer
+	Album           = model.Album
+	Track           = model.Track
+	Venue           = model.Venue
+	Concert         = model.Concert
+)
+
+// ConcertQuery selects the concerts that overlap with the time window [From, To). Zero values are not used as a
+// filter.
+type ConcertQuery struct {
+	From     time.Time
+	To       time.Time
+	VenueID  string
+	SingerID string
+	// PageSize is the number of concerts that are fetched per request, 100 by default and at most 1000.
+	PageSize int
+}
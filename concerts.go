@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultConcertLimit = 100
+	maxConcertLimit     = 1000
+)
+
+// ConcertFilter selects the concerts that overlap with the time window [From, To).
+// Zero values are not used as a filter.
+type ConcertFilter struct {
+	From     time.Time
+	To       time.Time
+	VenueId  string
+	SingerId string
+	Limit    int
+	Offset   int
+}
+
+// parseConcertFilter reads a ConcertFilter from the query parameters of the request.
+// Timestamps must be formatted as RFC3339, e.g. 2023-02-01T20:00:00-05:00.
+func parseConcertFilter(r *http.Request) (ConcertFilter, error) {
+	q := r.URL.Query()
+	filter := ConcertFilter{
+		VenueId:  q.Get("venue_id"),
+		SingerId: q.Get("singer_id"),
+		Limit:    defaultConcertLimit,
+	}
+	var err error
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, fmt.Errorf("invalid from parameter: %w", err)
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, fmt.Errorf("invalid to parameter: %w", err)
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return filter, errors.New("to must be after from")
+	}
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 || filter.Limit > maxConcertLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxConcertLimit)
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil || filter.Offset < 0 {
+			return filter, errors.New("offset must be a non-negative number")
+		}
+	}
+	return filter, nil
+}
+
+// FindConcerts returns the concerts matching the filter ordered by start time.
+// A concert matches the time window if it has not ended before From and starts before To.
+// Associations are only loaded if the given db is configured to preload them.
+func FindConcerts(db *gorm.DB, filter ConcertFilter) ([]*Concert, error) {
+	query := db.Model(&Concert{})
+	if !filter.From.IsZero() {
+		query = query.Where("end_time > ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("start_time < ?", filter.To)
+	}
+	if filter.VenueId != "" {
+		query = query.Where("venue_id = ?", filter.VenueId)
+	}
+	if filter.SingerId != "" {
+		query = query.Where("singer_id = ?", filter.SingerId)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	concerts := []*Concert{}
+	if err := query.Order("start_time, id").Find(&concerts).Error; err != nil {
+		return nil, err
+	}
+	return concerts, nil
+}
+
+func (m MusicDbOperation) listConcerts(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseConcertFilter(r)
+	if err != nil {
+		errorRender(w, r, http.StatusBadRequest, err)
+		return
+	}
+	concerts, err := m.repoFor(r.Context()).FindConcerts(r.Context(), filter, true)
+	if err != nil {
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	render.JSON(w, r, concerts)
+}
+
+func (m MusicDbOperation) exportVenueCalendar(w http.ResponseWriter, r *http.Request) {
+	venue, err := m.repoFor(r.Context()).GetVenue(r.Context(), chi.URLParam(r, "venueId"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorRender(w, r, http.StatusNotFound, errors.New("venue not found"))
+			return
+		}
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	m.exportCalendar(w, r, "Concerts at "+venue.Name, ConcertFilter{VenueId: venue.ID})
+}
+
+func (m MusicDbOperation) exportSingerCalendar(w http.ResponseWriter, r *http.Request) {
+	singer, err := m.repoFor(r.Context()).GetSinger(r.Context(), chi.URLParam(r, "singerId"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorRender(w, r, http.StatusNotFound, errors.New("singer not found"))
+			return
+		}
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	m.exportCalendar(w, r, "Concerts by "+singer.FullName, ConcertFilter{SingerId: singer.ID})
+}
+
+// exportCalendar writes the concerts matching the filter as an iCalendar (RFC 5545) feed, at most maxConcertLimit.
+// The from and to query parameters can be used to restrict the exported time window.
+func (m MusicDbOperation) exportCalendar(w http.ResponseWriter, r *http.Request, name string, filter ConcertFilter) {
+	window, err := parseConcertFilter(r)
+	if err != nil {
+		errorRender(w, r, http.StatusBadRequest, err)
+		return
+	}
+	filter.From, filter.To, filter.Limit = window.From, window.To, maxConcertLimit
+	concerts, err := m.repoFor(r.Context()).FindConcerts(r.Context(), filter, true)
+	if err != nil {
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="concerts.ics"`)
+	w.Write([]byte(toICalendar(name, concerts, time.Now())))
+}
+
+const icalTimeFormat = "20060102T150405Z"
+
+// toICalendar renders the concerts as a VCALENDAR with one VEVENT per concert.
+// All times are written in UTC, so the calendar does not need any VTIMEZONE components.
+func toICalendar(name string, concerts []*Concert, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICalLine(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//" + appName + "//Concert Calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICalText(name))
+	for _, concert := range concerts {
+		stamp := concert.UpdatedAt
+		if stamp.IsZero() {
+			stamp = now
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + concert.ID + "@" + appName)
+		line("DTSTAMP:" + stamp.UTC().Format(icalTimeFormat))
+		line("DTSTART:" + concert.StartTime.UTC().Format(icalTimeFormat))
+		line("DTEND:" + concert.EndTime.UTC().Format(icalTimeFormat))
+		line("SUMMARY:" + escapeICalText(concert.Name))
+		if concert.Venue.Name != "" {
+			line("LOCATION:" + escapeICalText(concert.Venue.Name))
+		}
+		if concert.Singer.FullName != "" {
+			line("DESCRIPTION:" + escapeICalText("Performed by "+concert.Singer.FullName))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(s string) string {
+	return icalTextEscaper.Replace(s)
+}
+
+// foldICalLine splits content lines longer than 75 octets as required by RFC 5545 section 3.1.
+// Lines are never split within a multi-octet UTF-8 sequence.
+func foldICalLine(s string) string {
+	const maxOctets = 75
+	if len(s) <= maxOctets {
+		return s
+	}
+	var b strings.Builder
+	limit := maxOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = maxOctets - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
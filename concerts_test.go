@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestFoldICalLine(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		line string
+		want []int
+	}{
+		{"short line", "SUMMARY:Concert", []int{15}},
+		{"75 octets", strings.Repeat("a", 75), []int{75}},
+		{"76 octets", strings.Repeat("a", 76), []int{75, 2}},
+		// Continuation lines start with a space, so they carry 74 octets of the line.
+		{"three lines", strings.Repeat("a", 75+74+1), []int{75, 75, 2}},
+		// The 75th octet is the first octet of an é, which is moved to the next line.
+		{"multibyte character at the limit", strings.Repeat("a", 74) + "éé", []int{74, 5}},
+		{"only multibyte characters", strings.Repeat("é", 40), []int{74, 7}},
+		{"four octet characters", "X:" + strings.Repeat("🎸", 20), []int{74, 9}},
+	} {
+		folded := foldICalLine(tc.line)
+		lines := strings.Split(folded, "\r\n")
+		var got []int
+		for i, line := range lines {
+			got = append(got, len(line))
+			if !utf8.ValidString(line) {
+				t.Errorf("%s: line %d splits a character: %q", tc.name, i, line)
+			}
+			if i > 0 && !strings.HasPrefix(line, " ") {
+				t.Errorf("%s: continuation line %d does not start with a space: %q", tc.name, i, line)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("%s: got lines of %v octets, want %v", tc.name, got, tc.want)
+		}
+		if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tc.line {
+			t.Errorf("%s: unfolded line %q differs from %q", tc.name, unfolded, tc.line)
+		}
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	for _, tc := range []struct {
+		text, want string
+	}{
+		{"Rock, Pop; Jazz", `Rock\, Pop\; Jazz`},
+		{`AC\DC`, `AC\\DC`},
+		{"first line\nsecond line", `first line\nsecond line`},
+		{"first line\r\nsecond line", `first line\nsecond line`},
+		{`\n is not a newline`, `\\n is not a newline`},
+		{"Café", "Café"},
+	} {
+		if got := escapeICalText(tc.text); got != tc.want {
+			t.Errorf("escapeICalText(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestExportCalendarIsLimited(t *testing.T) {
+	ctx := context.Background()
+	repo := seedMemoryRepository(t)
+	for i := 0; i < maxConcertLimit; i++ {
+		start := testConcertStart.Add(time.Duration(i+1) * 24 * time.Hour)
+		if err := repo.CreateConcert(ctx, &Concert{BaseModel: BaseModel{ID: fmt.Sprintf("c%04d", i)}, VenueId: "v1",
+			SingerId: "s1", StartTime: start, EndTime: start.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := MusicDbOperation{repo: repo, cfg: defaultConfig()}
+	w := httptest.NewRecorder()
+	m.exportCalendar(w, httptest.NewRequest(http.MethodGet, "/api/venues/v1/concerts.ics", nil), "Hall", ConcertFilter{VenueId: "v1"})
+	if got := strings.Count(w.Body.String(), "BEGIN:VEVENT"); w.Code != http.StatusOK || got != maxConcertLimit {
+		t.Errorf("got status %d with %d events, want %d events", w.Code, got, maxConcertLimit)
+	}
+	// The first concert by start time is exported, and the last one is left out.
+	if body := w.Body.String(); !strings.Contains(body, "UID:c1@") || strings.Contains(body, fmt.Sprintf("UID:c%04d@", maxConcertLimit-1)) {
+		t.Error("got other concerts than the first ones by start time")
+	}
+}
@@ -0,0 +1,541 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm/logger"
+)
+
+// Config holds the configuration of the application. Values are read from, in increasing order of precedence,
+// the defaults in defaultConfig, an optional YAML file, environment variables and command line flags.
+// The yaml, env and flag tags define the name of a field in each of these sources. Fields tagged with secret are
+// redacted when the configuration is printed.
+type Config struct {
+	// ConfigFile is the optional YAML file that the configuration is read from.
+	ConfigFile string `yaml:"-" env:"CONFIG_FILE" flag:"config" usage:"YAML configuration file"`
+	// InitData generates initial data and exits instead of starting the server.
+	InitData bool `yaml:"-" flag:"init" usage:"Generate initial data"`
+	// RunLoadTest runs a load test with the LoadTest settings and exits instead of starting the server.
+	RunLoadTest bool `yaml:"-" flag:"loadtest" usage:"Run a load test and print the report instead of starting the server"`
+	// SampleReport is the file that the sample run command writes its JSON report to, - for stdout.
+	SampleReport string `yaml:"-" flag:"sample-report" usage:"File that sample run writes the JSON report to, - for stdout"`
+	// SampleDataModel is the DDL file that the CreateTablesIfNotExist step of the sample run command executes.
+	SampleDataModel string `yaml:"-" flag:"sample-data-model" usage:"DDL file of the tables of sample run"`
+	// PrintConfig prints the effective configuration and exits.
+	PrintConfig bool `yaml:"-" flag:"print-config" usage:"Print the effective configuration with secrets redacted and exit"`
+
+	Port     string `yaml:"port" env:"PORT" flag:"port" usage:"HTTP port"`
+	GrpcPort string `yaml:"grpc_port" env:"GRPC_PORT" flag:"grpc-port" usage:"gRPC port"`
+
+	// ConnString is the connection string of PGAdapter, like "host=localhost port=5432".
+	// If empty, it is derived from ProjectID, InstanceName and DatabaseName. If it does not select a database, the
+	// database of these settings is added to it.
+	ConnString   string `yaml:"connection_string" env:"CONNECTION_STRING" flag:"connection-string" secret:"dsn" usage:"PGAdapter connection string"`
+	ProjectID    string `yaml:"project_id" env:"PROJECT_ID" flag:"project-id" usage:"Google Cloud project of the Spanner instance"`
+	InstanceName string `yaml:"instance_name" env:"INSTANCE_NAME" flag:"instance-name" usage:"Spanner instance"`
+	DatabaseName string `yaml:"database_name" env:"DATABASE_NAME" flag:"database-name" usage:"Spanner database"`
+	MaxRetry     int    `yaml:"max_retry" env:"MAX_RETRY" flag:"max-retry" usage:"Number of attempts to connect to PGAdapter at startup"`
+	// ConnectTimeout is the deadline for connecting to PGAdapter at startup, including all retries, and for opening
+	// the connection pool of a tenant.
+	ConnectTimeout time.Duration `yaml:"connect_timeout" env:"CONNECT_TIMEOUT" flag:"connect-timeout" usage:"Deadline for connecting to PGAdapter at startup"`
+
+	// Settings of the database/sql connection pool, see sql.DB. Zero means unlimited.
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"MAX_OPEN_CONNS" flag:"max-open-conns" usage:"Maximum number of open connections to PGAdapter"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"MAX_IDLE_CONNS" flag:"max-idle-conns" usage:"Maximum number of idle connections to PGAdapter"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"CONN_MAX_LIFETIME" flag:"conn-max-lifetime" usage:"Maximum time that a connection is reused"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"CONN_MAX_IDLE_TIME" flag:"conn-max-idle-time" usage:"Maximum time that a connection is idle before it is closed"`
+
+	// SpannerGuardStrict rejects statements that Cloud Spanner does not support, such as ON CONFLICT or savepoints,
+	// instead of logging a warning. See spannerGuard.
+	SpannerGuardStrict bool `yaml:"spanner_guard_strict" env:"SPANNER_GUARD_STRICT" flag:"spanner-guard-strict" usage:"Reject statements that Cloud Spanner does not support instead of logging a warning"`
+
+	// LogLevel is the gorm log level. The default info level shows the SQL that is generated by gorm.
+	LogLevel string `yaml:"log_level" env:"LOG_LEVEL" flag:"log-level" usage:"gorm log level: silent, error, warn or info"`
+	// SlowQueryThreshold is the duration above which statements are logged as warnings. Zero disables the warning.
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" env:"SLOW_QUERY_THRESHOLD" flag:"slow-query-threshold" usage:"Log statements that take longer as warnings"`
+	// LogRedactParams removes the parameters from the logged SQL, as they can contain personal data.
+	LogRedactParams bool `yaml:"log_redact_params" env:"LOG_REDACT_PARAMS" flag:"log-redact-params" usage:"Do not log the parameters of SQL statements"`
+
+	RequestTimeout     time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT" flag:"request-timeout" usage:"Timeout of HTTP requests"`
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT" flag:"health-check-timeout" usage:"Timeout of each database check of /readyz"`
+	// ShutdownDelay is the time that the server keeps serving requests while reporting unready after SIGTERM, so
+	// that the load balancer stops sending requests before the listeners are closed.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY" flag:"shutdown-delay" usage:"Time to keep serving while reporting unready after SIGTERM"`
+	// DrainPeriod is the maximum time that in-flight requests get to finish after the listeners are closed.
+	// Cloud Run kills the instance 10 seconds after SIGTERM, so ShutdownDelay + DrainPeriod should stay below that.
+	DrainPeriod time.Duration `yaml:"drain_period" env:"DRAIN_PERIOD" flag:"drain-period" usage:"Maximum time for in-flight requests to finish during shutdown"`
+	// Tenants are the labels whose catalogs are stored in their own database. Requests select a tenant with the
+	// tenant header or a subdomain of TenantDomain, and use the main database if they select none.
+	Tenants      map[string]TenantConfig `yaml:"tenants"`
+	TenantHeader string                  `yaml:"tenant_header" env:"TENANT_HEADER" flag:"tenant-header" usage:"Header that selects the tenant"`
+	TenantDomain string                  `yaml:"tenant_domain" env:"TENANT_DOMAIN" flag:"tenant-domain" usage:"Domain whose subdomains select the tenant, like music.example.com"`
+
+	// AuthEnabled requires callers of the /api, /graphql and /admin routes and of the gRPC API to authenticate with
+	// an API key or a JWT. Readers may call GET routes, editors also mutations, and admins also the /admin routes.
+	AuthEnabled bool `yaml:"auth_enabled" env:"AUTH_ENABLED" flag:"auth-enabled" usage:"Require authentication"`
+	// APIKeys have the format subject:role:key, for example ci:editor:s3cr3t.
+	APIKeys           []string `yaml:"api_keys" env:"API_KEYS" flag:"api-keys" secret:"true" usage:"Comma separated API keys in the format subject:role:key"`
+	JWTSecret         string   `yaml:"jwt_hs256_secret" env:"JWT_HS256_SECRET" flag:"jwt-hs256-secret" secret:"true" usage:"Secret that verifies HS256 tokens"`
+	JWTPublicKeyFiles []string `yaml:"jwt_rs256_public_keys" env:"JWT_RS256_PUBLIC_KEYS" flag:"jwt-rs256-public-keys" usage:"Comma separated PEM files of the public keys that verify RS256 tokens, named after their key ID"`
+	JWTIssuer         string   `yaml:"jwt_issuer" env:"JWT_ISSUER" flag:"jwt-issuer" usage:"Required issuer of tokens"`
+	JWTAudience       string   `yaml:"jwt_audience" env:"JWT_AUDIENCE" flag:"jwt-audience" usage:"Required audience of tokens"`
+
+	// Rate limits of the /api and /graphql routes and the gRPC API per client in requests per second, 0 disables the
+	// limit. Clients are identified by their authenticated subject or their IP.
+	ReadRateLimit     float64 `yaml:"read_rate_limit" env:"READ_RATE_LIMIT" flag:"read-rate-limit" usage:"GET requests per second per client, 0 for unlimited"`
+	ReadBurst         int     `yaml:"read_burst" env:"READ_BURST" flag:"read-burst" usage:"Burst of GET requests per client"`
+	WriteRateLimit    float64 `yaml:"write_rate_limit" env:"WRITE_RATE_LIMIT" flag:"write-rate-limit" usage:"Other requests per second per client, 0 for unlimited"`
+	WriteBurst        int     `yaml:"write_burst" env:"WRITE_BURST" flag:"write-burst" usage:"Burst of other requests per client"`
+	TrustForwardedFor bool    `yaml:"trust_forwarded_for" env:"TRUST_FORWARDED_FOR" flag:"trust-forwarded-for" usage:"Identify clients by the last X-Forwarded-For address"`
+	// MaxInFlight is the maximum number of rate limited requests that are executed concurrently, 0 for unlimited.
+	MaxInFlight int `yaml:"max_in_flight" env:"MAX_IN_FLIGHT" flag:"max-in-flight" usage:"Maximum number of concurrent API requests, 0 for unlimited"`
+	// IdempotencyTTL is the time that the responses of POST /api requests with an Idempotency-Key header are kept, so
+	// that retries return the response of the first request. Zero ignores the header.
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL" flag:"idempotency-ttl" usage:"Time that responses of requests with an Idempotency-Key are kept, 0 to ignore the header"`
+
+	// OpenAPIValidation is one of off, request or response. Response validation is intended for test environments.
+	OpenAPIValidation string `yaml:"openapi_validation" env:"OPENAPI_VALIDATION" flag:"openapi-validation" usage:"Validate against the OpenAPI document: off, request or response"`
+
+	// TraceExporter is one of none, stdout or otlp. The stdout exporter is intended for local development.
+	TraceExporter    string  `yaml:"trace_exporter" env:"TRACE_EXPORTER" flag:"trace-exporter" usage:"Exporter of OpenTelemetry spans: none, stdout or otlp"`
+	OTLPEndpoint     string  `yaml:"otlp_endpoint" env:"OTLP_ENDPOINT" flag:"otlp-endpoint" usage:"host:port of the OTLP gRPC collector"`
+	OTLPInsecure     bool    `yaml:"otlp_insecure" env:"OTLP_INSECURE" flag:"otlp-insecure" usage:"Connect to the OTLP collector without TLS"`
+	TraceSampleRatio float64 `yaml:"trace_sample_ratio" env:"TRACE_SAMPLE_RATIO" flag:"trace-sample-ratio" usage:"Fraction of traces that are sampled"`
+
+	// DataSeed seeds the generator of the sample and the initial data, so that the same data is generated again.
+	// Zero uses a random seed. Data that is generated by the handlers and the load test is always random.
+	DataSeed int64 `yaml:"data_seed" env:"DATA_SEED" flag:"data-seed" usage:"Seed of the generated sample and initial data, 0 for a random seed"`
+	// DataVocabulary is a YAML file with the words of the generated names and titles, see datagen.LoadVocabulary.
+	DataVocabulary string `yaml:"data_vocabulary" env:"DATA_VOCABULARY" flag:"data-vocabulary" usage:"YAML file with the words of generated names and titles"`
+
+	LoadTest LoadTestConfig `yaml:"loadtest"`
+	Replay   ReplayConfig   `yaml:"replay"`
+	// RecordRequests is a JSONL file that the /api and /graphql requests and their responses are appended to, so
+	// that they can be replayed with the replay command. Credentials are not recorded.
+	RecordRequests string `yaml:"record_requests" env:"RECORD_REQUESTS" flag:"record-requests" usage:"JSONL file that API requests and responses are recorded to"`
+
+	// FaultInjection injects errors and latency into statements and requests, for resilience tests in local and
+	// staging environments.
+	FaultInjection FaultInjectionConfig `yaml:"fault_injection"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		SampleReport:       "-",
+		SampleDataModel:    "schemas/create_data_model.sql",
+		Port:               "8080",
+		GrpcPort:           "9090",
+		MaxRetry:           10,
+		ConnectTimeout:     time.Minute,
+		MaxOpenConns:       100,
+		MaxIdleConns:       10,
+		ConnMaxLifetime:    30 * time.Minute,
+		ConnMaxIdleTime:    5 * time.Minute,
+		LogLevel:           "info",
+		SlowQueryThreshold: 200 * time.Millisecond,
+		RequestTimeout:     60 * time.Second,
+		HealthCheckTimeout: 2 * time.Second,
+		ShutdownDelay:      2 * time.Second,
+		DrainPeriod:        7 * time.Second,
+		TenantHeader:       "X-Tenant",
+		ReadRateLimit:      50,
+		ReadBurst:          100,
+		WriteRateLimit:     10,
+		WriteBurst:         20,
+		MaxInFlight:        200,
+		IdempotencyTTL:     time.Hour,
+		OpenAPIValidation:  "off",
+		TraceExporter:      "none",
+		OTLPEndpoint:       "localhost:4317",
+		TraceSampleRatio:   1,
+		LoadTest: LoadTestConfig{
+			Target:      loadTestRepository,
+			Mix:         []string{opRegisterSingerWithAlbum + "=1", opGetAlbumsOfSinger + "=6", opListConcerts + "=3"},
+			Concurrency: 10,
+			Rate:        50,
+			Duration:    30 * time.Second,
+			Format:      "table",
+		},
+		Replay: ReplayConfig{
+			Target:          "http://localhost:8080",
+			Speed:           1,
+			Concurrency:     1,
+			IgnoreFields:    []string{"MarketingBudget", "CoverPicture", "Tracks"},
+			IgnoreGenerated: true,
+			Format:          "table",
+		},
+	}
+}
+
+var logLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// GormLogLevel returns the gorm log level of the configuration.
+func (c *Config) GormLogLevel() logger.LogLevel {
+	return logLevels[c.LogLevel]
+}
+
+// DSN returns the connection string that is used to connect to PGAdapter.
+// If no connection string has been configured, the connection string is derived from the database settings. If the
+// connection string does not select a database, the database of the database settings is added to it.
+// PGAdapter accepts a fully qualified database name, so the database does not need to be set when PGAdapter is
+// started.
+func (c *Config) DSN() string {
+	database := ""
+	switch {
+	case c.ProjectID != "" && c.InstanceName != "" && c.DatabaseName != "":
+		database = fmt.Sprintf("projects/%s/instances/%s/databases/%s", c.ProjectID, c.InstanceName, c.DatabaseName)
+	case c.DatabaseName != "":
+		database = c.DatabaseName
+	}
+	dsn := c.ConnString
+	if dsn == "" {
+		dsn = "host=localhost port=5432"
+	}
+	if database != "" && !dsnDatabaseRegexp.MatchString(dsn) {
+		dsn += " database=" + database
+	}
+	return dsn
+}
+
+// dsnDatabaseRegexp matches connection strings that select a database.
+var dsnDatabaseRegexp = regexp.MustCompile(`(?i)(^|\s)(database|dbname)\s*=`)
+
+// Validate checks that the configuration can be used to start the application.
+func (c *Config) Validate() error {
+	var errs []string
+	for name, port := range map[string]string{"port": c.Port, "grpc_port": c.GrpcPort} {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			errs = append(errs, fmt.Sprintf("%s must be a number between 1 and 65535, got %q", name, port))
+		}
+	}
+	if c.Port == c.GrpcPort {
+		errs = append(errs, "port and grpc_port must be different")
+	}
+	if c.MaxRetry < 1 {
+		errs = append(errs, "max_retry must be at least 1")
+	}
+	if c.ConnectTimeout <= 0 {
+		errs = append(errs, "connect_timeout must be positive")
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 || c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		errs = append(errs, "connection pool settings must not be negative")
+	}
+	if _, ok := logLevels[c.LogLevel]; !ok {
+		errs = append(errs, fmt.Sprintf("log_level must be one of silent, error, warn or info, got %q", c.LogLevel))
+	}
+	if c.SlowQueryThreshold < 0 {
+		errs = append(errs, "slow_query_threshold must not be negative")
+	}
+	if c.RequestTimeout <= 0 {
+		errs = append(errs, "request_timeout must be positive")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		errs = append(errs, "health_check_timeout must be positive")
+	}
+	if c.ShutdownDelay < 0 {
+		errs = append(errs, "shutdown_delay must not be negative")
+	}
+	if c.DrainPeriod <= 0 {
+		errs = append(errs, "drain_period must be positive")
+	}
+	for name, tenant := range c.Tenants {
+		if name == "" || name != strings.ToLower(name) || strings.Contains(name, ".") || name == defaultTenant {
+			errs = append(errs, fmt.Sprintf("tenant %q must be a lowercase name without dots other than %s", name, defaultTenant))
+		}
+		if tenant.ConnString == "" && tenant.DatabaseName == "" {
+			errs = append(errs, fmt.Sprintf("tenant %s needs a connection_string or database_name", name))
+		}
+		if tenant.DatabaseName != "" && dsnDatabaseRegexp.MatchString(tenant.ConnString) {
+			errs = append(errs, fmt.Sprintf("tenant %s must select its database with either connection_string or database_name", name))
+		}
+		if tenant.MaxOpenConns < 0 || tenant.MaxIdleConns < 0 || tenant.MaxInFlight < 0 {
+			errs = append(errs, fmt.Sprintf("limits of tenant %s must not be negative", name))
+		}
+	}
+	if c.TenantHeader == "" {
+		errs = append(errs, "tenant_header must be set")
+	}
+	if c.AuthEnabled && len(c.APIKeys) == 0 && c.JWTSecret == "" && len(c.JWTPublicKeyFiles) == 0 {
+		errs = append(errs, "api_keys, jwt_hs256_secret or jwt_rs256_public_keys must be set if auth_enabled is set")
+	}
+	if c.ReadRateLimit < 0 || c.WriteRateLimit < 0 {
+		errs = append(errs, "read_rate_limit and write_rate_limit must not be negative")
+	}
+	if (c.ReadRateLimit > 0 && c.ReadBurst < 1) || (c.WriteRateLimit > 0 && c.WriteBurst < 1) {
+		errs = append(errs, "read_burst and write_burst must be at least 1 if the rate is limited")
+	}
+	if c.MaxInFlight < 0 {
+		errs = append(errs, "max_in_flight must not be negative")
+	}
+	if c.IdempotencyTTL < 0 {
+		errs = append(errs, "idempotency_ttl must not be negative")
+	}
+	switch c.OpenAPIValidation {
+	case "off", "request", "response":
+	default:
+		errs = append(errs, fmt.Sprintf("openapi_validation must be one of off, request or response, got %q", c.OpenAPIValidation))
+	}
+	switch c.TraceExporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Sprintf("trace_exporter must be one of none, stdout or otlp, got %q", c.TraceExporter))
+	}
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		errs = append(errs, "trace_sample_ratio must be between 0 and 1")
+	}
+	errs = append(errs, c.LoadTest.validate()...)
+	errs = append(errs, c.Replay.validate()...)
+	errs = append(errs, c.FaultInjection.validate()...)
+	if (c.ProjectID != "" || c.InstanceName != "") && c.DatabaseName == "" {
+		errs = append(errs, "database_name must be set if project_id or instance_name is set")
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// LoadConfig reads the configuration from the defaults, the YAML file, the environment and the command line
+// arguments, and validates the result.
+func LoadConfig(args []string) (*Config, error) {
+	cfg, rest, err := loadConfigWithArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", rest)
+	}
+	return cfg, nil
+}
+
+// loadConfigWithArgs is LoadConfig for commands that take arguments after the flags. It returns these arguments.
+func loadConfigWithArgs(args []string) (*Config, []string, error) {
+	cfg := defaultConfig()
+	fields := configFields(reflect.ValueOf(cfg).Elem())
+
+	fs := flag.NewFlagSet(appName, flag.ContinueOnError)
+	for _, f := range fields {
+		name := f.tag.Get("flag")
+		if name == "" {
+			continue
+		}
+		if f.value.Kind() == reflect.Bool {
+			fs.Bool(name, f.value.Bool(), f.tag.Get("usage"))
+		} else {
+			fs.String(name, configValueString(f.value), f.tag.Get("usage"))
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	setFlags := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = f.Value.String()
+	})
+
+	configFile := os.Getenv("CONFIG_FILE")
+	if v, ok := setFlags["config"]; ok {
+		configFile = v
+	}
+	if configFile != "" {
+		if err := cfg.loadYAML(configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, f := range fields {
+		if env := f.tag.Get("env"); env != "" {
+			if v, ok := os.LookupEnv(env); ok {
+				if err := setConfigValue(f.value, v); err != nil {
+					return nil, nil, fmt.Errorf("invalid value for %s: %w", env, err)
+				}
+			}
+		}
+	}
+	for _, f := range fields {
+		if v, ok := setFlags[f.tag.Get("flag")]; ok {
+			if err := setConfigValue(f.value, v); err != nil {
+				return nil, nil, fmt.Errorf("invalid value for -%s: %w", f.tag.Get("flag"), err)
+			}
+		}
+	}
+	cfg.ConfigFile = configFile
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+func (c *Config) loadYAML(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+	defer f.Close()
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteRedacted writes the configuration as YAML with all secrets redacted.
+func (c *Config) WriteRedacted(w io.Writer) error {
+	out := *c
+	for _, f := range configFields(reflect.ValueOf(&out).Elem()) {
+		redactConfigValue(f.value, f.tag.Get("secret"))
+	}
+	fmt.Fprintf(w, "# effective configuration of %s", appName)
+	if c.ConfigFile != "" {
+		fmt.Fprintf(w, " (config file: %s)", c.ConfigFile)
+	}
+	fmt.Fprintln(w)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(&out)
+}
+
+const redacted = "REDACTED"
+
+var dsnPasswordRegexp = regexp.MustCompile(`(?i)(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// redactedDSN returns the connection string with the password redacted, so that it can be logged.
+func redactedDSN(dsn string) string {
+	return dsnPasswordRegexp.ReplaceAllString(dsn, "${1}"+redacted)
+}
+
+func redactConfigValue(v reflect.Value, secret string) {
+	if v.Kind() == reflect.Map && v.Type().Elem().Kind() == reflect.Struct && v.Len() > 0 {
+		// Map values cannot be modified in place, so the secrets of copies of the values are redacted and the map is
+		// replaced, as the copy of the configuration shares the map.
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			for _, f := range configFields(value) {
+				redactConfigValue(f.value, f.tag.Get("secret"))
+			}
+			out.SetMapIndex(iter.Key(), value)
+		}
+		v.Set(out)
+		return
+	}
+	if secret != "" && v.Kind() == reflect.Slice && v.Len() > 0 {
+		// Replace the slice instead of its elements, as the copy of the configuration shares the backing array.
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = redacted
+		}
+		v.Set(reflect.ValueOf(values))
+		return
+	}
+	if secret == "" || v.Kind() != reflect.String || v.String() == "" {
+		return
+	}
+	if secret == "dsn" {
+		// Only the password of a connection string is secret.
+		v.SetString(redactedDSN(v.String()))
+		return
+	}
+	v.SetString(redacted)
+}
+
+// configField is a configurable field of Config. Fields of nested structs are returned as separate fields.
+type configField struct {
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+func configFields(v reflect.Value) []configField {
+	var fields []configField
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			fields = append(fields, configFields(v.Field(i))...)
+			continue
+		}
+		fields = append(fields, configField{value: v.Field(i), tag: field.Tag})
+	}
+	return fields
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setConfigValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %v", v.Type())
+		}
+		var values []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+func configValueString(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice {
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unsetEnv removes the environment variable for the duration of the test.
+func unsetEnv(t *testing.T, name string) {
+	t.Helper()
+	t.Setenv(name, "")
+	os.Unsetenv(name)
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	yamlFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(yamlFile, []byte("read_burst: 20\nrequest_timeout: 20s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name        string
+		yaml        bool
+		env         map[string]string
+		args        []string
+		wantBurst   int
+		wantTimeout time.Duration
+	}{
+		{name: "defaults", wantBurst: 100, wantTimeout: 60 * time.Second},
+		{name: "yaml over defaults", yaml: true, wantBurst: 20, wantTimeout: 20 * time.Second},
+		{name: "env over yaml", yaml: true, env: map[string]string{"READ_BURST": "30"}, wantBurst: 30, wantTimeout: 20 * time.Second},
+		{name: "flags over env", yaml: true, env: map[string]string{"READ_BURST": "30", "REQUEST_TIMEOUT": "30s"},
+			args: []string{"-read-burst", "40"}, wantBurst: 40, wantTimeout: 30 * time.Second},
+		{name: "flags over defaults", args: []string{"-request-timeout", "40s"}, wantBurst: 100, wantTimeout: 40 * time.Second},
+	} {
+		for _, name := range []string{"CONFIG_FILE", "READ_BURST", "REQUEST_TIMEOUT"} {
+			unsetEnv(t, name)
+		}
+		if tc.yaml {
+			t.Setenv("CONFIG_FILE", yamlFile)
+		}
+		for name, value := range tc.env {
+			t.Setenv(name, value)
+		}
+		cfg, _, err := loadConfigWithArgs(tc.args)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if cfg.ReadBurst != tc.wantBurst || cfg.RequestTimeout != tc.wantTimeout {
+			t.Errorf("%s: got read_burst %d and request_timeout %v, want %d and %v", tc.name, cfg.ReadBurst,
+				cfg.RequestTimeout, tc.wantBurst, tc.wantTimeout)
+		}
+	}
+
+	// The config flag selects another file than the environment.
+	unsetEnv(t, "READ_BURST")
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	if cfg, _, err := loadConfigWithArgs([]string{"-config", yamlFile}); err != nil || cfg.ReadBurst != 20 || cfg.ConfigFile != yamlFile {
+		t.Errorf("got %v with the config flag", err)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		yaml string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{name: "unknown YAML key", yaml: "read_brust: 20\n", want: "field read_brust not found"},
+		{name: "invalid env value", env: map[string]string{"READ_BURST": "many"}, want: "invalid value for READ_BURST"},
+		{name: "invalid flag value", args: []string{"-request-timeout", "soon"}, want: "invalid value for -request-timeout"},
+		{name: "same ports", args: []string{"-port", "8080", "-grpc-port", "8080"}, want: "port and grpc_port must be different"},
+		{name: "negative rate limit", args: []string{"-read-rate-limit", "-1"}, want: "read_rate_limit and write_rate_limit must not be negative"},
+		{name: "auth without credentials", args: []string{"-auth-enabled"}, want: "must be set if auth_enabled is set"},
+		{name: "tenant without database", yaml: "tenants:\n  label1: {max_in_flight: 5}\n", want: "tenant label1 needs a connection_string or database_name"},
+		{name: "tenant with two databases", yaml: "tenants:\n  label1: {connection_string: \"host=pg database=a\", database_name: b}\n",
+			want: "tenant label1 must select its database with either connection_string or database_name"},
+		{name: "several errors", args: []string{"-log-level", "loud", "-trace-exporter", "jaeger"},
+			want: "log_level must be one of silent, error, warn or info, got \"loud\"\n  trace_exporter must be one of none, stdout or otlp"},
+	} {
+		for _, name := range []string{"CONFIG_FILE", "READ_BURST"} {
+			unsetEnv(t, name)
+		}
+		if tc.yaml != "" {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tc.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("CONFIG_FILE", path)
+		}
+		for name, value := range tc.env {
+			t.Setenv(name, value)
+		}
+		if _, _, err := loadConfigWithArgs(tc.args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestConfigDSN(t *testing.T) {
+	for _, tc := range []struct {
+		name                                    string
+		connString, project, instance, database string
+		want                                    string
+	}{
+		{"defaults", "", "", "", "", "host=localhost port=5432"},
+		{"database settings", "", "p", "i", "music", "host=localhost port=5432 database=projects/p/instances/i/databases/music"},
+		{"database name only", "", "", "", "music", "host=localhost port=5432 database=music"},
+		{"connection string without database", "host=pgadapter port=5433", "p", "i", "music",
+			"host=pgadapter port=5433 database=projects/p/instances/i/databases/music"},
+		{"connection string with database", "host=pgadapter database=game", "p", "i", "music", "host=pgadapter database=game"},
+		{"connection string with dbname", "host=pgadapter dbname = game", "", "", "music", "host=pgadapter dbname = game"},
+		{"connection string only", "host=pgadapter", "", "", "", "host=pgadapter"},
+	} {
+		cfg := defaultConfig()
+		cfg.ConnString, cfg.ProjectID, cfg.InstanceName, cfg.DatabaseName = tc.connString, tc.project, tc.instance, tc.database
+		if got := cfg.DSN(); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAdminConfigRedactsSecrets(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.ConnString = "host=pgadapter port=5432 password='pg secret'"
+	cfg.JWTSecret = "jwt-secret"
+	cfg.APIKeys = []string{"ci:editor:key-secret"}
+	cfg.Replay.APIKey = "replay-secret"
+	cfg.Tenants = map[string]TenantConfig{"label1": {ConnString: "host=other password=tenant-secret"}}
+	m := MusicDbOperation{cfg: cfg}
+
+	w := httptest.NewRecorder()
+	m.adminConfig(w, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+	body := w.Body.String()
+	for _, secret := range []string{"pg secret", "jwt-secret", "key-secret", "replay-secret", "tenant-secret"} {
+		if strings.Contains(body, secret) {
+			t.Errorf("the configuration contains %q:\n%s", secret, body)
+		}
+	}
+	for _, want := range []string{"host=pgadapter port=5432 password=REDACTED", "jwt_hs256_secret: REDACTED",
+		"- REDACTED", "host=other password=REDACTED", "read_burst: 100"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("the configuration does not contain %q:\n%s", want, body)
+		}
+	}
+
+	// Redaction does not change the configuration that is used.
+	if cfg.JWTSecret != "jwt-secret" || cfg.APIKeys[0] != "ci:editor:key-secret" ||
+		cfg.Tenants["label1"].ConnString != "host=other password=tenant-secret" {
+		t.Errorf("the configuration was modified: %+v", cfg)
+	}
+}
+
+func TestDefaultShutdownFitsCloudRun(t *testing.T) {
+	cfg := defaultConfig()
+	// Cloud Run stops the instance 10 seconds after SIGTERM.
+	if cfg.ShutdownDelay <= 0 || cfg.ShutdownDelay+cfg.DrainPeriod >= 10*time.Second {
+		t.Errorf("got shutdown_delay %v and drain_period %v, want a delay and a sum below 10s", cfg.ShutdownDelay, cfg.DrainPeriod)
+	}
+}
+
+func TestRunReturnsConfigurationErrors(t *testing.T) {
+	unsetEnv(t, "CONFIG_FILE")
+	if err := run([]string{"-log-level", "loud"}); err == nil || !strings.Contains(err.Error(), "log_level") {
+		t.Errorf("got %v, want an invalid configuration", err)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// maxStatementAttempts is the number of times that a statement outside a transaction is executed when the
+// connection to PGAdapter fails.
+const maxStatementAttempts = 3
+
+// backoff returns the time to wait before the given retry attempt (starting at 1). It grows exponentially from
+// initial up to max, with full jitter so that instances that lost PGAdapter at the same time do not retry in lockstep.
+func backoff(attempt int, initial, max time.Duration) time.Duration {
+	d := initial
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+// sleepContext waits for d, or returns false if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// isConnectionError returns true if err means that the connection to PGAdapter failed, for example because PGAdapter
+// is restarted by supervisord. Such errors are transient, as database/sql opens a new connection for the next use.
+// Timeouts are not connection errors: the deadline of the statement has expired, so a retry would fail as well.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || pgconn.Timeout(err) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection exception, 57P01 is sent to the clients when PGAdapter shuts down.
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01"
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		(errors.As(err, &netErr) && !netErr.Timeout())
+}
+
+// safeToRetry returns true if err occurred before the statement was sent to PGAdapter.
+func safeToRetry(err error) bool {
+	var retryable interface{ SafeToRetry() bool }
+	return errors.As(err, &retryable) && retryable.SafeToRetry()
+}
+
+// isReadOnlyQuery returns true if query is a SELECT. Other statements that return rows, such as INSERT ... RETURNING,
+// modify data.
+func isReadOnlyQuery(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	return len(query) >= len("SELECT") && strings.EqualFold(query[:len("SELECT")], "SELECT")
+}
+
+// queryRetryable returns the function that decides whether query is retried after an error: SELECT statements are
+// retried after all connection errors, and other statements only if they were not sent.
+func queryRetryable(query string) func(error) bool {
+	if isReadOnlyQuery(query) {
+		return isConnectionError
+	}
+	return safeToRetry
+}
+
+// retryingConnPool wraps the connection pool to retry statements outside transactions that fail because of a
+// connection failure, so that a PGAdapter restart does not surface as errors to clients. SELECT statements are always
+// retried, as they do not modify data. Other statements, including INSERT ... RETURNING that GORM sends as a query,
+// are only retried if they were not sent, as they could otherwise be applied twice. Statements in a transaction use the sql.Tx and are retried as a whole by runTransaction.
+type retryingConnPool struct {
+	db *sql.DB
+}
+
+var (
+	_ gorm.ConnPool       = (*retryingConnPool)(nil)
+	_ gorm.TxBeginner     = (*retryingConnPool)(nil)
+	_ gorm.GetDBConnector = (*retryingConnPool)(nil)
+)
+
+func (p *retryingConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+func (p *retryingConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	var stmt *sql.Stmt
+	err := retryStatement(ctx, isConnectionError, func() (err error) {
+		stmt, err = p.db.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, err
+}
+
+func (p *retryingConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var res sql.Result
+	err := retryStatement(ctx, safeToRetry, func() (err error) {
+		res, err = p.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+func (p *retryingConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := retryStatement(ctx, queryRetryable(query), func() (err error) {
+		rows, err = p.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (p *retryingConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	_ = retryStatement(ctx, queryRetryable(query), func() error {
+		row = p.db.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+func (p *retryingConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := retryStatement(ctx, isConnectionError, func() (err error) {
+		tx, err = p.db.BeginTx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// retryStatement executes fn until it succeeds, fails with an error for which retryable returns false, or the
+// attempts are exhausted.
+func retryStatement(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt == maxStatementAttempts || !retryable(err) {
+			return err
+		}
+		dbStatementRetries.Inc()
+		if !sleepContext(ctx, backoff(attempt, 50*time.Millisecond, time.Second)) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/gorm"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+func TestRetryingConnPoolOnlyRetriesReads(t *testing.T) {
+	server, db := newFakeDb(t)
+	if err := configureConnPool(db, defaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	server.On(`FROM "singers"`, pgfake.Disconnect(), pgfake.Rows([]pgfake.Column{{Name: "id", OID: pgtype.TextOID}},
+		[]interface{}{"s1"}))
+	var singers []*Singer
+	if err := db.Find(&singers).Error; err != nil || len(singers) != 1 {
+		t.Fatalf("got %d singers, %v, want the singer of the retried query", len(singers), err)
+	}
+	if got := len(statementsWith(server, `FROM "singers"`)); got != 2 {
+		t.Errorf("got %d queries, want 2", got)
+	}
+
+	// Without the default transaction of GORM, the insert uses the pool.
+	server.On(`INSERT INTO "singers"`, pgfake.Disconnect())
+	if _, err := CreateSinger(db.Session(&gorm.Session{SkipDefaultTransaction: true}), "Alice", "Smith"); err == nil {
+		t.Error("got no error for the insert on the lost connection")
+	}
+	if got := len(statementsWith(server, `INSERT INTO "singers"`)); got != 1 {
+		t.Errorf("got %d inserts, want 1", got)
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("write: %w", syscall.ECONNRESET), true},
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{&net.OpError{Op: "read", Err: timeoutError{}}, false},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), false},
+		{context.Canceled, false},
+	} {
+		if got := isConnectionError(tc.err); got != tc.want {
+			t.Errorf("%v: got %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
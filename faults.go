@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/datagen"
+)
+
+// Scopes of fault rules: statements are the statements that GORM executes, requests the requests of the /api and
+// /graphql routes.
+const (
+	faultScopeStatement = "statement"
+	faultScopeRequest   = "request"
+)
+
+// Faults that are injected into statements and requests.
+const (
+	// faultAbort fails a statement with the error that PGAdapter returns if Cloud Spanner aborted the transaction.
+	faultAbort = "abort"
+	// faultDeadline fails a statement with context.DeadlineExceeded.
+	faultDeadline = "deadline"
+	// faultDisconnect fails a statement with driver.ErrBadConn, as if the connection to PGAdapter was dropped.
+	faultDisconnect = "disconnect"
+	// faultLatency delays a statement or request by the latency of the rule.
+	faultLatency = "latency"
+	// faultError responds to a request with the status of the rule.
+	faultError = "error"
+)
+
+var (
+	statementFaults = []string{faultAbort, faultDeadline, faultDisconnect, faultLatency}
+	requestFaults   = []string{faultError, faultLatency}
+	// statementOperations are the GORM callbacks that faults can be injected into.
+	statementOperations = []string{"create", "query", "update", "delete", "row", "raw"}
+	requestMethods      = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+)
+
+var faultInjections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "fault_injections_total",
+	Help: "Number of injected faults by scope (statement or request) and fault.",
+}, []string{"scope", "fault"})
+
+// FaultInjectionConfig configures the faults that are injected into a percentage of the statements and requests to
+// test how the application handles aborted transactions, timeouts, dropped connections and slow statements. It is
+// also the body of the /admin/faults endpoint, which changes the configuration of a running server.
+type FaultInjectionConfig struct {
+	Enabled bool        `yaml:"enabled" json:"enabled" env:"FAULT_INJECTION_ENABLED" flag:"fault-injection-enabled" usage:"Inject the faults of fault_injection.rules"`
+	Rules   []FaultRule `yaml:"rules" json:"rules"`
+}
+
+// FaultRule injects a fault into a percentage of the statements or requests that match its filters. Empty filters
+// match everything.
+type FaultRule struct {
+	// Scope is statement or request.
+	Scope string `yaml:"scope" json:"scope"`
+	// Fault is abort, deadline, disconnect or latency for statements, and error or latency for requests.
+	Fault   string  `yaml:"fault" json:"fault"`
+	Percent float64 `yaml:"percent" json:"percent"`
+	// Latency is the delay of the latency fault.
+	Latency time.Duration `yaml:"latency,omitempty" json:"latency,omitempty"`
+	// Status is the HTTP status of the error fault, 503 by default.
+	Status int `yaml:"status,omitempty" json:"status,omitempty"`
+	// Tables filters statements by the table of their model.
+	Tables []string `yaml:"tables,omitempty" json:"tables,omitempty"`
+	// Operations filters statements by GORM operation (create, query, update, delete, row or raw) and requests by
+	// HTTP method.
+	Operations []string `yaml:"operations,omitempty" json:"operations,omitempty"`
+	// Paths filters requests by path prefix, like /api/stats.
+	Paths []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+}
+
+// MarshalJSON encodes the latency as a duration string like 200ms.
+func (r FaultRule) MarshalJSON() ([]byte, error) {
+	type rule FaultRule
+	out := struct {
+		rule
+		Latency string `json:"latency,omitempty"`
+	}{rule: rule(r)}
+	if r.Latency != 0 {
+		out.Latency = r.Latency.String()
+	}
+	return json.Marshal(out)
+}
+
+func (r *FaultRule) UnmarshalJSON(data []byte) error {
+	type rule FaultRule
+	in := struct {
+		*rule
+		Latency string `json:"latency,omitempty"`
+	}{rule: (*rule)(r)}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	r.Latency = 0
+	if in.Latency != "" {
+		latency, err := time.ParseDuration(in.Latency)
+		if err != nil {
+			return fmt.Errorf("invalid latency: %w", err)
+		}
+		r.Latency = latency
+	}
+	return nil
+}
+
+// validate returns the problems of the fault injection configuration.
+func (c FaultInjectionConfig) validate() []string {
+	var errs []string
+	for i, rule := range c.Rules {
+		for _, err := range rule.validate() {
+			errs = append(errs, fmt.Sprintf("fault_injection.rules[%d]: %s", i, err))
+		}
+	}
+	return errs
+}
+
+func (r FaultRule) validate() []string {
+	var errs []string
+	switch r.Scope {
+	case faultScopeStatement:
+		if !containsString(statementFaults, r.Fault) {
+			errs = append(errs, fmt.Sprintf("fault of a statement rule must be one of %s, got %q", strings.Join(statementFaults, ", "), r.Fault))
+		}
+		for _, op := range r.Operations {
+			if !containsString(statementOperations, op) {
+				errs = append(errs, fmt.Sprintf("operations of a statement rule must be %s, got %q", strings.Join(statementOperations, ", "), op))
+			}
+		}
+		if r.Status != 0 || len(r.Paths) > 0 {
+			errs = append(errs, "status and paths only apply to request rules")
+		}
+	case faultScopeRequest:
+		if !containsString(requestFaults, r.Fault) {
+			errs = append(errs, fmt.Sprintf("fault of a request rule must be one of %s, got %q", strings.Join(requestFaults, ", "), r.Fault))
+		}
+		for _, method := range r.Operations {
+			if !containsString(requestMethods, method) {
+				errs = append(errs, fmt.Sprintf("operations of a request rule must be %s, got %q", strings.Join(requestMethods, ", "), method))
+			}
+		}
+		if r.Status != 0 && (r.Fault != faultError || r.Status < 400 || r.Status > 599) {
+			errs = append(errs, "status must be between 400 and 599 and only applies to the error fault")
+		}
+		for _, path := range r.Paths {
+			if !strings.HasPrefix(path, "/") {
+				errs = append(errs, fmt.Sprintf("paths must start with /, got %q", path))
+			}
+		}
+		if len(r.Tables) > 0 {
+			errs = append(errs, "tables only apply to statement rules")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("scope must be %s or %s, got %q", faultScopeStatement, faultScopeRequest, r.Scope))
+	}
+	if r.Percent <= 0 || r.Percent > 100 {
+		errs = append(errs, "percent must be greater than 0 and at most 100")
+	}
+	if (r.Fault == faultLatency) != (r.Latency > 0) {
+		errs = append(errs, "latency must be positive for the latency fault and is not allowed for other faults")
+	}
+	return errs
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// faultInjector holds the fault injection configuration of the process. It is shared by the GORM plugins of all
+// tenants and the HTTP middleware, and can be changed at runtime through /admin/faults.
+type faultInjector struct {
+	mu  sync.RWMutex
+	cfg FaultInjectionConfig
+	// rndMu guards rnd, as rules are picked concurrently under the read lock.
+	rndMu sync.Mutex
+	rnd   *rand.Rand
+}
+
+var faultInjection = &faultInjector{rnd: rand.New(rand.NewSource(datagen.NewSeed()))}
+
+// roll returns true with the given percentage.
+func (f *faultInjector) roll(percent float64) bool {
+	f.rndMu.Lock()
+	defer f.rndMu.Unlock()
+	return f.rnd.Float64()*100 < percent
+}
+
+// config returns a copy of the current configuration.
+func (f *faultInjector) config() FaultInjectionConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return FaultInjectionConfig{Enabled: f.cfg.Enabled, Rules: append([]FaultRule{}, f.cfg.Rules...)}
+}
+
+// set replaces the configuration. It must have been validated.
+func (f *faultInjector) set(cfg FaultInjectionConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cfg = FaultInjectionConfig{Enabled: cfg.Enabled, Rules: append([]FaultRule{}, cfg.Rules...)}
+}
+
+// pick returns the first rule of scope that matches and is selected by its percentage, or false if no fault should
+// be injected.
+func (f *faultInjector) pick(scope string, matches func(FaultRule) bool) (FaultRule, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if !f.cfg.Enabled {
+		return FaultRule{}, false
+	}
+	for _, rule := range f.cfg.Rules {
+		if rule.Scope == scope && matches(rule) && f.roll(rule.Percent) {
+			faultInjections.WithLabelValues(scope, rule.Fault).Inc()
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
+
+// matchesFilter returns true if the filter is empty or contains value.
+func matchesFilter(filter []string, value string) bool {
+	return len(filter) == 0 || containsString(filter, value)
+}
+
+// statementFaultError returns the error of a statement fault, like the driver or PGAdapter would return it.
+func statementFaultError(fault string) error {
+	switch fault {
+	case faultAbort:
+		return &pgconn.PgError{Severity: "ERROR", Code: "40001", Message: "injected fault: transaction was aborted"}
+	case faultDeadline:
+		return fmt.Errorf("injected fault: %w", context.DeadlineExceeded)
+	case faultDisconnect:
+		return fmt.Errorf("injected fault: %w", driver.ErrBadConn)
+	}
+	return nil
+}
+
+// gormFaults is a GORM plugin that injects the statement faults of faultInjection before a statement is executed.
+// An injected error skips the statement and rolls back the transaction of a single statement, like a real error.
+type gormFaults struct{}
+
+func (gormFaults) Name() string {
+	return "faults"
+}
+
+func (p gormFaults) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	// The faults are injected after the metrics plugin has started the timer, so that injected latency is measured.
+	for _, err := range []error{
+		cb.Create().After("metrics:before_create").Before("gorm:create").Register("faults:create", p.inject("create")),
+		cb.Query().After("metrics:before_query").Before("gorm:query").Register("faults:query", p.inject("query")),
+		cb.Update().After("metrics:before_update").Before("gorm:update").Register("faults:update", p.inject("update")),
+		cb.Delete().After("metrics:before_delete").Before("gorm:delete").Register("faults:delete", p.inject("delete")),
+		cb.Row().After("metrics:before_row").Before("gorm:row").Register("faults:row", p.inject("row")),
+		cb.Raw().After("metrics:before_raw").Before("gorm:raw").Register("faults:raw", p.inject("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gormFaults) inject(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil {
+			return
+		}
+		rule, ok := faultInjection.pick(faultScopeStatement, func(rule FaultRule) bool {
+			return matchesFilter(rule.Operations, operation) && matchesFilter(rule.Tables, db.Statement.Table)
+		})
+		if !ok {
+			return
+		}
+		if rule.Fault == faultLatency {
+			if ctx := db.Statement.Context; !sleepContext(ctx, rule.Latency) {
+				db.AddError(ctx.Err())
+			}
+			return
+		}
+		db.AddError(statementFaultError(rule.Fault))
+	}
+}
+
+// middleware injects the request faults of the injector before the request is handled.
+func (f *faultInjector) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule, ok := f.pick(faultScopeRequest, func(rule FaultRule) bool {
+			if !matchesFilter(rule.Operations, r.Method) {
+				return false
+			}
+			for _, path := range rule.Paths {
+				if strings.HasPrefix(r.URL.Path, path) {
+					return true
+				}
+			}
+			return len(rule.Paths) == 0
+		})
+		if ok {
+			if rule.Fault == faultError {
+				status := rule.Status
+				if status == 0 {
+					status = http.StatusServiceUnavailable
+				}
+				errorRender(w, r, status, errors.New("injected fault"))
+				return
+			}
+			if !sleepContext(r.Context(), rule.Latency) {
+				errorRender(w, r, http.StatusServiceUnavailable, r.Context().Err())
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminGetFaults returns the fault injection configuration.
+func (m MusicDbOperation) adminGetFaults(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, faultInjection.config())
+}
+
+// adminPutFaults replaces the fault injection configuration of this instance until it is restarted.
+func (m MusicDbOperation) adminPutFaults(w http.ResponseWriter, r *http.Request) {
+	var cfg FaultInjectionConfig
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		errorRender(w, r, http.StatusBadRequest, fmt.Errorf("invalid fault injection configuration: %w", err))
+		return
+	}
+	if errs := cfg.validate(); len(errs) > 0 {
+		errorRender(w, r, http.StatusBadRequest, fmt.Errorf("invalid fault injection configuration: %s", strings.Join(errs, "; ")))
+		return
+	}
+	faultInjection.set(cfg)
+	render.JSON(w, r, faultInjection.config())
+}
+
+// adminDeleteFaults disables fault injection and removes all rules.
+func (m MusicDbOperation) adminDeleteFaults(w http.ResponseWriter, r *http.Request) {
+	faultInjection.set(FaultInjectionConfig{})
+	render.JSON(w, r, faultInjection.config())
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// setFaults configures faultInjection for the test and disables it again afterwards.
+func setFaults(t *testing.T, rules ...FaultRule) {
+	t.Helper()
+	cfg := FaultInjectionConfig{Enabled: true, Rules: rules}
+	if errs := cfg.validate(); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	faultInjection.set(cfg)
+	t.Cleanup(func() { faultInjection.set(FaultInjectionConfig{}) })
+}
+
+func TestStatementFaultsAreFilteredByTable(t *testing.T) {
+	server, db := newFakeDb(t)
+	if err := db.Use(gormFaults{}); err != nil {
+		t.Fatal(err)
+	}
+	setFaults(t, FaultRule{Scope: faultScopeStatement, Fault: faultAbort, Percent: 100, Tables: []string{"venues"}, Operations: []string{"create"}})
+
+	attempts := 0
+	err := runTransaction(db, func(tx *gorm.DB) error {
+		attempts++
+		return tx.Create(&Venue{BaseModel: BaseModel{ID: "v1"}, Name: "Venue"}).Error
+	})
+	if !isAbortedError(err) {
+		t.Fatalf("got error %v, want an aborted error", err)
+	}
+	if attempts != maxTransactionAttempts {
+		t.Errorf("attempts: got %d, want %d", attempts, maxTransactionAttempts)
+	}
+	if got := len(statementsWith(server, `INSERT INTO "venues"`)); got != 0 {
+		t.Errorf("got %d venue inserts, want none", got)
+	}
+
+	if err := db.Where("id = ?", "v1").Delete(&Venue{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := len(statementsWith(server, `DELETE FROM "venues"`)); got != 1 {
+		t.Errorf("venue deletes: got %d, want 1", got)
+	}
+}
+
+func TestRequestFaults(t *testing.T) {
+	setFaults(t, FaultRule{Scope: faultScopeRequest, Fault: faultError, Percent: 100, Status: http.StatusBadGateway,
+		Operations: []string{http.MethodGet}, Paths: []string{"/api/stats"}})
+
+	r := chi.NewRouter()
+	r.Use(faultInjection.middleware)
+	r.Get("/api/*", func(w http.ResponseWriter, r *http.Request) {})
+	for path, want := range map[string]int{"/api/stats/singers": http.StatusBadGateway, "/api/concerts": http.StatusOK} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("%s: got status %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestAdminFaults(t *testing.T) {
+	t.Cleanup(func() { faultInjection.set(FaultInjectionConfig{}) })
+	m := MusicDbOperation{}
+	r := chi.NewRouter()
+	r.Get("/admin/faults", m.adminGetFaults)
+	r.Put("/admin/faults", m.adminPutFaults)
+	r.Delete("/admin/faults", m.adminDeleteFaults)
+	do := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/admin/faults", strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodPut, `{"enabled": true, "rules": [{"scope": "statement", "fault": "latency", "percent": 10, "latency": "50ms"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body)
+	}
+	cfg := faultInjection.config()
+	if !cfg.Enabled || len(cfg.Rules) != 1 || cfg.Rules[0].Latency != 50*time.Millisecond {
+		t.Errorf("got configuration %+v", cfg)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(do(http.MethodGet, "").Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if latency := got["rules"].([]interface{})[0].(map[string]interface{})["latency"]; latency != "50ms" {
+		t.Errorf("got latency %v, want 50ms", latency)
+	}
+
+	if w := do(http.MethodPut, `{"enabled": true, "rules": [{"scope": "request", "fault": "abort", "percent": 10}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid rule: got status %d, want 400", w.Code)
+	}
+	if w := do(http.MethodDelete, ""); w.Code != http.StatusOK || faultInjection.config().Enabled {
+		t.Errorf("delete: got status %d, enabled %v", w.Code, faultInjection.config().Enabled)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/datagen"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate the golden files in testdata/sql")
+
+// unsupportedSQL contains fragments of generated SQL that Cloud Spanner does not support.
+var unsupportedSQL = []string{"ON CONFLICT", "SAVEPOINT", "RETURNING"}
+
+// sampleOperations are the sample operations whose generated SQL is compared with a golden file: the steps of the
+// sample, and the functions that the steps use to create records. CreateTablesIfNotExist is not included, as it only
+// executes the statements in create_data_model.sql.
+func sampleOperations() []sampleStep {
+	var ops []sampleStep
+	for _, step := range sampleSteps {
+		if step.name != "CreateTablesIfNotExist" {
+			ops = append(ops, step)
+		}
+	}
+	return append(ops,
+		sampleStep{name: "CreateSinger", run: func(db *gorm.DB) error {
+			_, err := CreateSinger(db, "Alice", "Smith")
+			return err
+		}},
+		sampleStep{name: "CreateAlbumWithRandomTracks", run: func(db *gorm.DB) error {
+			_, err := CreateAlbumWithRandomTracks(db, "singer1", "Album", 10)
+			return err
+		}},
+	)
+}
+
+// sqlRecorder records the statements that gorm generates in DryRun mode, and the transactions that it starts.
+type sqlRecorder struct {
+	lines []string
+}
+
+func (r *sqlRecorder) add(line string) {
+	r.lines = append(r.lines, line)
+}
+
+func (r *sqlRecorder) String() string {
+	return strings.Join(r.lines, "\n") + "\n"
+}
+
+var errDryRun = errors.New("dry run: statements are not executed")
+
+// dryRunPool is a gorm.ConnPool that never executes a statement, but records when transactions are started and
+// ended. gorm does not use the pool for any other statements in DryRun mode.
+type dryRunPool struct {
+	recorder *sqlRecorder
+}
+
+func (p *dryRunPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errDryRun
+}
+
+func (p *dryRunPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errDryRun
+}
+
+func (p *dryRunPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+
+func (p *dryRunPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *dryRunPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	p.recorder.add("BEGIN;")
+	return &dryRunTx{p.recorder}, nil
+}
+
+// dryRunTx is the gorm.ConnPool of a transaction. It is not a gorm.ConnPoolBeginner, so gorm does not start a
+// transaction for a single statement in a transaction.
+type dryRunTx struct {
+	recorder *sqlRecorder
+}
+
+func (tx *dryRunTx) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errDryRun
+}
+
+func (tx *dryRunTx) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errDryRun
+}
+
+func (tx *dryRunTx) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+
+func (tx *dryRunTx) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (tx *dryRunTx) Commit() error {
+	tx.recorder.add("COMMIT;")
+	return nil
+}
+
+func (tx *dryRunTx) Rollback() error {
+	tx.recorder.add("ROLLBACK;")
+	return nil
+}
+
+// newDryRunDb opens a gorm connection in DryRun mode that records all generated statements.
+//
+// As nothing is executed, additional callbacks fake the results that the sample operations check. This ensures that
+// the operations get past these checks, without looping forever.
+func newDryRunDb(t *testing.T) (*sqlRecorder, *gorm.DB) {
+	t.Helper()
+	recorder := &sqlRecorder{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunPool{recorder}}), &gorm.Config{
+		DryRun:                   true,
+		DisableNestedTransaction: true,
+		Logger:                   logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := func(db *gorm.DB) {
+		if db.Statement.SQL.Len() > 0 {
+			recorder.add(db.Statement.SQL.String() + ";")
+		}
+	}
+	// Each statement affects one row.
+	changed := func(db *gorm.DB) {
+		db.RowsAffected = 1
+	}
+	// A query for a single record finds a record, and a query for a slice of records finds nothing. A singer that is
+	// reloaded gets the full name that is generated by the database.
+	found := func(db *gorm.DB) {
+		switch dest := db.Statement.Dest.(type) {
+		case *Singer:
+			if dest.ID == "" {
+				dest.ID = "singer1"
+			}
+			dest.FullName = strings.TrimSpace(dest.FirstName.String + " " + dest.LastName)
+		case *Album:
+			dest.ID = "album1"
+		case *Track:
+			dest.ID, dest.TrackNumber = "album1", 1
+		case *Venue:
+			dest.ID = "venue1"
+		default:
+			return
+		}
+		db.RowsAffected = 1
+	}
+	callbacks := db.Callback()
+	register := []error{
+		callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("golden:record", record),
+		callbacks.Create().After("golden:record").Before("gorm:commit_or_rollback_transaction").Register("golden:result", changed),
+		callbacks.Query().After("gorm:query").Before("gorm:preload").Register("golden:record", record),
+		callbacks.Query().After("golden:record").Before("gorm:preload").Register("golden:result", found),
+		callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("golden:record", record),
+		callbacks.Update().After("golden:record").Before("gorm:commit_or_rollback_transaction").Register("golden:result", changed),
+		callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("golden:record", record),
+		callbacks.Delete().After("golden:record").Before("gorm:commit_or_rollback_transaction").Register("golden:result", changed),
+		callbacks.Row().After("gorm:row").Register("golden:record", record),
+		callbacks.Raw().After("gorm:raw").Register("golden:record", record),
+	}
+	for _, err := range register {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return recorder, db
+}
+
+// TestGeneratedSQL runs each sample operation in DryRun mode and compares the generated statements with the golden
+// files in testdata/sql. Run `go test -run TestGeneratedSQL -update` to regenerate the golden files after a change
+// to the sample or an upgrade of gorm, and review the differences before committing them.
+func TestGeneratedSQL(t *testing.T) {
+	for _, op := range sampleOperations() {
+		t.Run(op.name, func(t *testing.T) {
+			recorder, db := newDryRunDb(t)
+			// The number of generated records depends on the random generator.
+			sampleData = datagen.New(1, nil)
+			if err := op.run(db); err != nil {
+				recorder.add("-- error: " + err.Error())
+			}
+			got := recorder.String()
+			for _, fragment := range unsupportedSQL {
+				if strings.Contains(strings.ToUpper(got), fragment) {
+					t.Errorf("generated SQL contains %s, which is not supported by Cloud Spanner", fragment)
+				}
+			}
+
+			golden := filepath.Join("testdata", "sql", op.name+".sql")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -run TestGeneratedSQL -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("generated SQL differs from %s\n got:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
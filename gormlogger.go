@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// jsonGormLogger is a gorm logger that writes JSON through the zerolog logger of httplog, so that the statements
+// end up in Cloud Logging in the same format as the HTTP requests and can be correlated by request ID.
+type jsonGormLogger struct {
+	log           zerolog.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+	redactParams  bool
+}
+
+var _ gorm.ParamsFilter = jsonGormLogger{}
+
+// newJsonGormLogger returns a gorm logger that logs with log according to the log settings of cfg.
+func newJsonGormLogger(log zerolog.Logger, cfg *Config) logger.Interface {
+	return jsonGormLogger{
+		log:           log.With().Str("component", "gorm").Logger(),
+		level:         cfg.GormLogLevel(),
+		slowThreshold: cfg.SlowQueryThreshold,
+		redactParams:  cfg.LogRedactParams,
+	}
+}
+
+func (l jsonGormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	l.level = level
+	return l
+}
+
+func (l jsonGormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		l.event(ctx, l.log.Info()).Msg(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l jsonGormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		l.event(ctx, l.log.Warn()).Msg(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l jsonGormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		l.event(ctx, l.log.Error()).Msg(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs failed statements as errors, statements that exceed the slow query threshold as warnings, and all
+// other statements at info level.
+func (l jsonGormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	var e *zerolog.Event
+	var msg string
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		e, msg = l.log.Error().Err(err), "statement failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		e, msg = l.log.Warn().Dur("slowThreshold", l.slowThreshold), "slow statement"
+	case l.level >= logger.Info:
+		e, msg = l.log.Info(), "statement"
+	default:
+		return
+	}
+	sql, rows := fc()
+	e = l.event(ctx, e).
+		Str("sql", sql).
+		Float64("elapsed", float64(elapsed.Nanoseconds())/1e6). // in milliseconds, like the httplog request logs
+		Str("caller", gormCaller())
+	if rows >= 0 {
+		e = e.Int64("rows", rows)
+	}
+	e.Msg(msg)
+}
+
+// ParamsFilter replaces the parameters of statements in the logs if redaction is enabled, as they can contain
+// personal data such as names.
+func (l jsonGormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if !l.redactParams {
+		return sql, params
+	}
+	redacted := make([]interface{}, len(params))
+	for i := range redacted {
+		redacted[i] = "REDACTED"
+	}
+	return sql, redacted
+}
+
+// event adds the request ID and the caller of ctx to e.
+func (l jsonGormLogger) event(ctx context.Context, e *zerolog.Event) *zerolog.Event {
+	if ctx == nil {
+		return e
+	}
+	if id := middleware.GetReqID(ctx); id != "" {
+		e = e.Str("requestID", id)
+	}
+	if id := identityFrom(ctx); id != nil {
+		e = e.Str("user", id.Subject)
+	}
+	return e
+}
+
+// gormCaller returns the file and line of the application code that executed the statement. It is the equivalent of
+// utils.FileWithLineNum, which would return this file as it only skips the gorm sources.
+func gormCaller() string {
+	for i := 2; i < 20; i++ {
+		_, file, line, ok := runtime.Caller(i)
+		if !ok {
+			break
+		}
+		if !strings.Contains(file, "gorm.io/") && !strings.HasSuffix(file, "/gormlogger.go") {
+			return file + ":" + strconv.Itoa(line)
+		}
+	}
+	return ""
+}
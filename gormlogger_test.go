@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+// logLines returns the JSON log lines of buf.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func TestJsonGormLoggerLevels(t *testing.T) {
+	statement := func() (string, int64) { return `SELECT * FROM "singers"`, 2 }
+	for _, tc := range []struct {
+		name     string
+		logLevel string
+		elapsed  time.Duration
+		err      error
+		// want is the level of the log line, or empty if the statement is not logged.
+		want, wantMsg string
+	}{
+		{"failed statement", "info", 0, errors.New("lost connection"), "error", "statement failed"},
+		{"record not found", "info", 0, gorm.ErrRecordNotFound, "info", "statement"},
+		{"slow statement", "info", time.Second, nil, "warn", "slow statement"},
+		{"statement", "info", 0, nil, "info", "statement"},
+		{"statement at warn level", "warn", 0, nil, "", ""},
+		{"slow statement at warn level", "warn", time.Second, nil, "warn", "slow statement"},
+		{"slow statement at error level", "error", time.Second, nil, "", ""},
+		{"failed statement at error level", "error", 0, errors.New("lost connection"), "error", "statement failed"},
+		{"failed statement when silent", "silent", 0, errors.New("lost connection"), "", ""},
+	} {
+		cfg := defaultConfig()
+		cfg.LogLevel, cfg.SlowQueryThreshold = tc.logLevel, 200*time.Millisecond
+		var buf bytes.Buffer
+		l := newJsonGormLogger(zerolog.New(&buf), cfg)
+		l.Trace(context.Background(), time.Now().Add(-tc.elapsed), statement, tc.err)
+
+		lines := logLines(t, &buf)
+		if tc.want == "" {
+			if len(lines) != 0 {
+				t.Errorf("%s: got %v, want no log line", tc.name, lines)
+			}
+			continue
+		}
+		if len(lines) != 1 {
+			t.Fatalf("%s: got %d log lines, want 1", tc.name, len(lines))
+		}
+		line := lines[0]
+		if line[zerolog.LevelFieldName] != tc.want || line[zerolog.MessageFieldName] != tc.wantMsg {
+			t.Errorf("%s: got %v, want level %s with message %q", tc.name, line, tc.want, tc.wantMsg)
+		}
+		if line["sql"] != `SELECT * FROM "singers"` || line["rows"] != 2.0 || line["component"] != "gorm" {
+			t.Errorf("%s: got %v, want the statement, its rows and the gorm component", tc.name, line)
+		}
+		if caller, _ := line["caller"].(string); !strings.Contains(caller, "gormlogger_test.go") {
+			t.Errorf("%s: got caller %q, want this test", tc.name, caller)
+		}
+	}
+}
+
+func TestJsonGormLoggerRedactsParams(t *testing.T) {
+	for _, redact := range []bool{false, true} {
+		cfg := defaultConfig()
+		cfg.LogRedactParams = redact
+		filter := newJsonGormLogger(zerolog.Nop(), cfg).(gorm.ParamsFilter)
+		sql, params := filter.ParamsFilter(context.Background(), "SELECT $1, $2", "Alice", 42)
+		want := []interface{}{"Alice", 42}
+		if redact {
+			want = []interface{}{"REDACTED", "REDACTED"}
+		}
+		if sql != "SELECT $1, $2" || len(params) != 2 || params[0] != want[0] || params[1] != want[1] {
+			t.Errorf("redact %v: got %q, %v, want %v", redact, sql, params, want)
+		}
+	}
+
+	// GORM logs the statements with the filtered parameters.
+	for _, redact := range []bool{false, true} {
+		server, db := newFakeDb(t)
+		server.On(`FROM "singers"`, pgfake.Rows(nil))
+		cfg := defaultConfig()
+		cfg.LogRedactParams = redact
+		var buf bytes.Buffer
+		db.Logger = newJsonGormLogger(zerolog.New(&buf), cfg)
+		var singers []*Singer
+		if err := db.Where("last_name = ?", "Jones").Find(&singers).Error; err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(buf.String(), "Jones"); got == redact {
+			t.Errorf("redact %v: got the parameter in the log %v, want %v:\n%s", redact, got, !redact, buf.String())
+		}
+		if got := strings.Contains(buf.String(), "REDACTED"); got != redact {
+			t.Errorf("redact %v: got REDACTED in the log %v, want %v:\n%s", redact, got, redact, buf.String())
+		}
+	}
+}
+
+func TestJsonGormLoggerAddsRequestFields(t *testing.T) {
+	server, err := pgfake.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	cfg := defaultConfig()
+	// Each tenant registers the metrics of its pool, so the name must not be used by other tests.
+	cfg.Tenants = map[string]TenantConfig{"logged": {ConnString: server.DSN()}}
+	var buf bytes.Buffer
+	tr := newTenantRouter(cfg, zerolog.New(&buf))
+	defer tr.close()
+	tenant, err := tr.tenant(context.Background(), "logged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+
+	// The request ID and the caller come from the context of the statement, and the tenant from the logger of its
+	// connection pool.
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+	ctx = withIdentity(ctx, &Identity{Subject: "label1-ci", Role: roleEditor, Method: "api_key"})
+	if _, err := tenant.repo.ListSingers(ctx, Page{Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	lines := logLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1:\n%s", len(lines), buf.String())
+	}
+	if line := lines[0]; line["requestID"] != "req-1" || line["user"] != "label1-ci" || line["tenant"] != "logged" {
+		t.Errorf("got %v, want the request ID, user and tenant", line)
+	}
+
+	// The other log methods add the request fields of the context as well.
+	buf.Reset()
+	l := newJsonGormLogger(zerolog.New(&buf), cfg).LogMode(logger.Info)
+	l.Info(context.Background(), "opened %s", "pool")
+	l.Warn(ctx, "slow %s", "pool")
+	lines = logLines(t, &buf)
+	if len(lines) != 2 || lines[0][zerolog.MessageFieldName] != "opened pool" || lines[0]["requestID"] != nil ||
+		lines[1][zerolog.LevelFieldName] != "warn" || lines[1]["requestID"] != "req-1" {
+		t.Errorf("got %v", lines)
+	}
+}
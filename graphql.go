@@ -0,0 +1,834 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+	"gorm.io/gorm/schema"
+)
+
+// graphqlRequest is the body of a GraphQL request. GET requests pass the same values as query parameters.
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (m MusicDbOperation) graphqlHandler(gqlSchema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := graphqlRequest{}
+		if r.Method == http.MethodGet {
+			req.Query = r.URL.Query().Get("query")
+			req.OperationName = r.URL.Query().Get("operationName")
+			if v := r.URL.Query().Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					errorRender(w, r, http.StatusBadRequest, fmt.Errorf("invalid variables: %w", err))
+					return
+				}
+			}
+		} else {
+			defer r.Body.Close()
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				errorRender(w, r, http.StatusBadRequest, fmt.Errorf("invalid parameters in your request: %w", err))
+				return
+			}
+		}
+		// Mutations must not be sent with GET, so that they cannot be triggered cross-site and are rate limited as writes.
+		if r.Method == http.MethodGet && graphqlOperationType(req.Query, req.OperationName) == ast.OperationTypeMutation {
+			w.Header().Set("Allow", http.MethodPost)
+			errorRender(w, r, http.StatusMethodNotAllowed, errors.New("mutations must be sent with POST"))
+			return
+		}
+		result := graphql.Do(graphql.Params{
+			Schema:         gqlSchema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        withGraphqlLoaders(r.Context(), m.repoFor(r.Context())),
+		})
+		render.JSON(w, r, result)
+	}
+}
+
+// graphqlOperationType returns the type of the operation of query that operationName selects, or an empty string if
+// the query is invalid or does not contain the operation. Invalid queries are reported by graphql.Do.
+func graphqlOperationType(query, operationName string) string {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return ""
+	}
+	var ops []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			if operationName == "" || op.Name != nil && op.Name.Value == operationName {
+				ops = append(ops, op)
+			}
+		}
+	}
+	if len(ops) != 1 {
+		return ""
+	}
+	return ops[0].Operation
+}
+
+// newGraphqlSchema builds the GraphQL schema of the music model. The scalar fields of each object type are derived
+// from the gorm schema of the corresponding model, so that new columns automatically become visible. Associations
+// are added explicitly and are resolved by the batch loaders in graphqlLoaders.
+func (m MusicDbOperation) newGraphqlSchema() (graphql.Schema, error) {
+	var (
+		singerType, albumType, trackType, venueType, concertType *graphql.Object
+		err                                                      error
+	)
+	singerType, err = graphqlObjectFromModel(&Singer{}, "A singer that has released albums and performs at concerts.", func() graphql.Fields {
+		return graphql.Fields{
+			"albums": &graphql.Field{
+				Type: graphql.NewList(albumType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).albumsBySinger.Load(p.Source.(*Singer).ID)
+				},
+			},
+			"concerts": &graphql.Field{
+				Type: graphql.NewList(concertType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).concertsBySinger.Load(p.Source.(*Singer).ID)
+				},
+			},
+		}
+	})
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+	albumType, err = graphqlObjectFromModel(&Album{}, "An album released by a singer.", func() graphql.Fields {
+		return graphql.Fields{
+			"singer": &graphql.Field{
+				Type: singerType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).singers.Load(p.Source.(*Album).SingerId)
+				},
+			},
+			"tracks": &graphql.Field{
+				Type: graphql.NewList(trackType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).tracksByAlbum.Load(p.Source.(*Album).ID)
+				},
+			},
+		}
+	})
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+	trackType, err = graphqlObjectFromModel(&Track{}, "A track of an album. The id of a track is the id of its album.", func() graphql.Fields {
+		return graphql.Fields{
+			"album": &graphql.Field{
+				Type: albumType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).albums.Load(p.Source.(*Track).ID)
+				},
+			},
+		}
+	})
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+	venueType, err = graphqlObjectFromModel(&Venue{}, "A venue where concerts are held.", func() graphql.Fields {
+		return graphql.Fields{
+			"concerts": &graphql.Field{
+				Type: graphql.NewList(concertType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).concertsByVenue.Load(p.Source.(*Venue).ID)
+				},
+			},
+		}
+	})
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+	concertType, err = graphqlObjectFromModel(&Concert{}, "A concert of a singer at a venue.", func() graphql.Fields {
+		return graphql.Fields{
+			"venue": &graphql.Field{
+				Type: venueType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).venues.Load(p.Source.(*Concert).VenueId)
+				},
+			},
+			"singer": &graphql.Field{
+				Type: singerType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).singers.Load(p.Source.(*Concert).SingerId)
+				},
+			},
+		}
+	})
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+
+	pageArgs := graphql.FieldConfigArgument{
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultConcertLimit},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"singers": &graphql.Field{
+				Type: graphql.NewList(singerType),
+				Args: pageArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					singers, err := graphqlRepo(p).ListSingers(p.Context, graphqlPage(p.Args))
+					if err != nil {
+						return nil, err
+					}
+					loadersFrom(p.Context).primeSingers(singers)
+					return singers, nil
+				},
+			},
+			"singer": &graphql.Field{
+				Type: singerType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).singers.Load(p.Args["id"].(string))
+				},
+			},
+			"albums": &graphql.Field{
+				Type: graphql.NewList(albumType),
+				Args: graphql.FieldConfigArgument{
+					"singerId": &graphql.ArgumentConfig{Type: graphql.ID},
+					"limit":    pageArgs["limit"],
+					"offset":   pageArgs["offset"],
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					singerId, _ := p.Args["singerId"].(string)
+					albums, err := graphqlRepo(p).ListAlbums(p.Context, singerId, graphqlPage(p.Args))
+					if err != nil {
+						return nil, err
+					}
+					loadersFrom(p.Context).primeAlbums(albums)
+					return albums, nil
+				},
+			},
+			"album": &graphql.Field{
+				Type: albumType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).albums.Load(p.Args["id"].(string))
+				},
+			},
+			"venues": &graphql.Field{
+				Type: graphql.NewList(venueType),
+				Args: pageArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					venues, err := graphqlRepo(p).ListVenues(p.Context, graphqlPage(p.Args))
+					if err != nil {
+						return nil, err
+					}
+					loadersFrom(p.Context).primeVenues(venues)
+					return venues, nil
+				},
+			},
+			"venue": &graphql.Field{
+				Type: venueType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).venues.Load(p.Args["id"].(string))
+				},
+			},
+			"concerts": &graphql.Field{
+				Type: graphql.NewList(concertType),
+				Args: graphql.FieldConfigArgument{
+					"from":     &graphql.ArgumentConfig{Type: graphql.DateTime},
+					"to":       &graphql.ArgumentConfig{Type: graphql.DateTime},
+					"venueId":  &graphql.ArgumentConfig{Type: graphql.ID},
+					"singerId": &graphql.ArgumentConfig{Type: graphql.ID},
+					"limit":    pageArgs["limit"],
+					"offset":   pageArgs["offset"],
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page := graphqlPage(p.Args)
+					filter := ConcertFilter{Limit: page.Limit, Offset: page.Offset}
+					if v, ok := p.Args["from"].(time.Time); ok {
+						filter.From = v
+					}
+					if v, ok := p.Args["to"].(time.Time); ok {
+						filter.To = v
+					}
+					filter.VenueId, _ = p.Args["venueId"].(string)
+					filter.SingerId, _ = p.Args["singerId"].(string)
+					concerts, err := graphqlRepo(p).FindConcerts(p.Context, filter, false)
+					if err != nil {
+						return nil, err
+					}
+					loadersFrom(p.Context).primeConcerts(concerts)
+					return concerts, nil
+				},
+			},
+		},
+	})
+
+	trackInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "TrackInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"sampleRate": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createSinger": &graphql.Field{
+				Type: singerType,
+				Args: graphql.FieldConfigArgument{
+					"firstName": &graphql.ArgumentConfig{Type: graphql.String},
+					"lastName":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"active":    &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: true},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					singer := &Singer{BaseModel: BaseModel{ID: uuid.NewString()}, LastName: p.Args["lastName"].(string), Active: p.Args["active"].(bool)}
+					if v, ok := p.Args["firstName"].(string); ok {
+						singer.FirstName = sql.NullString{String: v, Valid: true}
+					}
+					if err := graphqlRepo(p).CreateSinger(p.Context, singer); err != nil {
+						return nil, err
+					}
+					return singer, nil
+				},
+			},
+			"updateSinger": &graphql.Field{
+				Type: singerType,
+				Args: graphql.FieldConfigArgument{
+					"id":        idArgs["id"],
+					"firstName": &graphql.ArgumentConfig{Type: graphql.String},
+					"lastName":  &graphql.ArgumentConfig{Type: graphql.String},
+					"active":    &graphql.ArgumentConfig{Type: graphql.Boolean},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return graphqlRepo(p).UpdateSinger(p.Context, p.Args["id"].(string), graphqlUpdates(p.Args, map[string]string{
+						"firstName": "first_name", "lastName": "last_name", "active": "active",
+					}))
+				},
+			},
+			"createAlbum": &graphql.Field{
+				Type: albumType,
+				Args: graphql.FieldConfigArgument{
+					"singerId":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"title":           &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"marketingBudget": &graphql.ArgumentConfig{Type: graphqlDecimal},
+					"releaseDate":     &graphql.ArgumentConfig{Type: graphqlDate},
+					"tracks":          &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(trackInput))},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					album := &Album{BaseModel: BaseModel{ID: uuid.NewString()}, SingerId: p.Args["singerId"].(string), Title: p.Args["title"].(string)}
+					if v, ok := p.Args["marketingBudget"].(decimal.Decimal); ok {
+						album.MarketingBudget = decimal.NullDecimal{Decimal: v, Valid: true}
+					}
+					if v, ok := p.Args["releaseDate"].(datatypes.Date); ok {
+						album.ReleaseDate = v
+					}
+					var tracks []*Track
+					if inputs, ok := p.Args["tracks"].([]interface{}); ok {
+						for n, input := range inputs {
+							fields := input.(map[string]interface{})
+							tracks = append(tracks, &Track{
+								BaseModel:   BaseModel{ID: album.ID},
+								TrackNumber: int64(n + 1),
+								Title:       fields["title"].(string),
+								SampleRate:  fields["sampleRate"].(float64),
+							})
+						}
+					}
+					// The album must be created before its tracks, see CreateAlbumWithRandomTracks.
+					if err := graphqlRepo(p).Transaction(p.Context, func(tx MusicRepository) error {
+						if err := tx.CreateAlbum(p.Context, album); err != nil {
+							return err
+						}
+						return tx.CreateTracks(p.Context, tracks)
+					}); err != nil {
+						return nil, err
+					}
+					return album, nil
+				},
+			},
+			"updateAlbum": &graphql.Field{
+				Type: albumType,
+				Args: graphql.FieldConfigArgument{
+					"id":              idArgs["id"],
+					"title":           &graphql.ArgumentConfig{Type: graphql.String},
+					"marketingBudget": &graphql.ArgumentConfig{Type: graphqlDecimal},
+					"releaseDate":     &graphql.ArgumentConfig{Type: graphqlDate},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return graphqlRepo(p).UpdateAlbum(p.Context, p.Args["id"].(string), graphqlUpdates(p.Args, map[string]string{
+						"title": "title", "marketingBudget": "marketing_budget", "releaseDate": "release_date",
+					}))
+				},
+			},
+			"createVenue": &graphql.Field{
+				Type: venueType,
+				Args: graphql.FieldConfigArgument{
+					"name":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"description": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					venue := &Venue{BaseModel: BaseModel{ID: uuid.NewString()}, Name: p.Args["name"].(string), Description: p.Args["description"].(string)}
+					if err := graphqlRepo(p).CreateVenue(p.Context, venue); err != nil {
+						return nil, err
+					}
+					return venue, nil
+				},
+			},
+			"updateVenue": &graphql.Field{
+				Type: venueType,
+				Args: graphql.FieldConfigArgument{
+					"id":          idArgs["id"],
+					"name":        &graphql.ArgumentConfig{Type: graphql.String},
+					"description": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return graphqlRepo(p).UpdateVenue(p.Context, p.Args["id"].(string), graphqlUpdates(p.Args, map[string]string{
+						"name": "name", "description": "description",
+					}))
+				},
+			},
+			"createConcert": &graphql.Field{
+				Type: concertType,
+				Args: graphql.FieldConfigArgument{
+					"venueId":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"singerId":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"name":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"startTime": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.DateTime)},
+					"endTime":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.DateTime)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					concert := &Concert{
+						BaseModel: BaseModel{ID: uuid.NewString()},
+						VenueId:   p.Args["venueId"].(string),
+						SingerId:  p.Args["singerId"].(string),
+						Name:      p.Args["name"].(string),
+						StartTime: p.Args["startTime"].(time.Time),
+						EndTime:   p.Args["endTime"].(time.Time),
+					}
+					if err := graphqlRepo(p).CreateConcert(p.Context, concert); err != nil {
+						return nil, err
+					}
+					return concert, nil
+				},
+			},
+			"updateConcert": &graphql.Field{
+				Type: concertType,
+				Args: graphql.FieldConfigArgument{
+					"id":        idArgs["id"],
+					"venueId":   &graphql.ArgumentConfig{Type: graphql.ID},
+					"singerId":  &graphql.ArgumentConfig{Type: graphql.ID},
+					"name":      &graphql.ArgumentConfig{Type: graphql.String},
+					"startTime": &graphql.ArgumentConfig{Type: graphql.DateTime},
+					"endTime":   &graphql.ArgumentConfig{Type: graphql.DateTime},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return graphqlRepo(p).UpdateConcert(p.Context, p.Args["id"].(string), graphqlUpdates(p.Args, map[string]string{
+						"venueId": "venue_id", "singerId": "singer_id", "name": "name", "startTime": "start_time", "endTime": "end_time",
+					}))
+				},
+			},
+		},
+	})
+	// Queries only require the reader role, which is checked by the router, but mutations require the editor role.
+	for _, field := range mutation.Fields() {
+		resolve := field.Resolve
+		field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+			if err := checkRole(p.Context, roleEditor); err != nil {
+				return nil, err
+			}
+			return resolve(p)
+		}
+	}
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func graphqlRepo(p graphql.ResolveParams) MusicRepository {
+	return loadersFrom(p.Context).repo
+}
+
+// graphqlPage returns the page of the limit and offset arguments. The limit is clamped to 1..maxConcertLimit like the
+// limit of /api/concerts, as a limit of 0 would return all records, and the loaders query the associations of all
+// records of the page.
+func graphqlPage(args map[string]interface{}) Page {
+	limit, _ := args["limit"].(int)
+	offset, _ := args["offset"].(int)
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > maxConcertLimit {
+		limit = maxConcertLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return Page{Limit: limit, Offset: offset}
+}
+
+// graphqlUpdates returns the column updates for all arguments that were specified.
+func graphqlUpdates(args map[string]interface{}, columns map[string]string) map[string]interface{} {
+	updates := map[string]interface{}{}
+	for arg, column := range columns {
+		if v, ok := args[arg]; ok {
+			switch v := v.(type) {
+			case decimal.Decimal:
+				updates[column] = decimal.NullDecimal{Decimal: v, Valid: true}
+			default:
+				updates[column] = v
+			}
+		}
+	}
+	return updates
+}
+
+var graphqlDecimal = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Decimal",
+	Description: "An exact decimal number. The value is serialized as a string to prevent loss of precision.",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case decimal.Decimal:
+			return v.String()
+		case decimal.NullDecimal:
+			if v.Valid {
+				return v.Decimal.String()
+			}
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case string:
+			if d, err := decimal.NewFromString(v); err == nil {
+				return d
+			}
+		case float64:
+			return decimal.NewFromFloat(v)
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		switch v := valueAST.(type) {
+		case *ast.StringValue, *ast.IntValue, *ast.FloatValue:
+			if d, err := decimal.NewFromString(v.GetValue().(string)); err == nil {
+				return d
+			}
+		}
+		return nil
+	},
+})
+
+var graphqlDate = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Date",
+	Description: "A calendar date without a time zone, formatted as YYYY-MM-DD.",
+	Serialize: func(value interface{}) interface{} {
+		if v, ok := value.(datatypes.Date); ok && !time.Time(v).IsZero() {
+			return time.Time(v).Format("2006-01-02")
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		if v, ok := value.(string); ok {
+			return parseGraphqlDate(v)
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if v, ok := valueAST.(*ast.StringValue); ok {
+			return parseGraphqlDate(v.Value)
+		}
+		return nil
+	},
+})
+
+func parseGraphqlDate(s string) interface{} {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return datatypes.Date(t)
+}
+
+var graphqlSchemaCache = &sync.Map{}
+
+// graphqlObjectFromModel creates a GraphQL object type with one field per column of the given gorm model.
+// Additional fields, typically associations, are added by the extra thunk, which is evaluated lazily so that
+// object types can refer to each other.
+func graphqlObjectFromModel(model interface{}, description string, extra graphql.FieldsThunk) (*graphql.Object, error) {
+	modelSchema, err := schema.Parse(model, graphqlSchemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	fields := graphql.Fields{}
+	for _, field := range modelSchema.Fields {
+		if field.DBName == "" || !field.Readable {
+			continue
+		}
+		fieldType := graphqlTypeOf(field)
+		if fieldType == nil {
+			// Binary columns such as the cover picture are not exposed.
+			continue
+		}
+		field := field
+		fields[graphqlFieldName(field.Name)] = &graphql.Field{
+			Type: fieldType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				value, _ := field.ValueOf(p.Context, reflect.ValueOf(p.Source).Elem())
+				if v, ok := value.(sql.NullString); ok {
+					if !v.Valid {
+						return nil, nil
+					}
+					return v.String, nil
+				}
+				return value, nil
+			},
+		}
+	}
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        modelSchema.Name,
+		Description: description,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			for name, field := range extra() {
+				fields[name] = field
+			}
+			return fields
+		}),
+	}), nil
+}
+
+func graphqlTypeOf(field *schema.Field) graphql.Output {
+	switch field.FieldType {
+	case reflect.TypeOf(sql.NullString{}):
+		return graphql.String
+	case reflect.TypeOf(decimal.NullDecimal{}):
+		return graphqlDecimal
+	case reflect.TypeOf(datatypes.Date{}):
+		return graphqlDate
+	case reflect.TypeOf(time.Time{}):
+		return graphql.NewNonNull(graphql.DateTime)
+	}
+	switch field.FieldType.Kind() {
+	case reflect.String:
+		if field.PrimaryKey || strings.HasSuffix(field.Name, "Id") {
+			return graphql.NewNonNull(graphql.ID)
+		}
+		return graphql.NewNonNull(graphql.String)
+	case reflect.Bool:
+		return graphql.NewNonNull(graphql.Boolean)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return graphql.NewNonNull(graphql.Int)
+	case reflect.Float32, reflect.Float64:
+		return graphql.NewNonNull(graphql.Float)
+	}
+	return nil
+}
+
+// graphqlFieldName converts a Go field name to a GraphQL field name, e.g. FirstName -> firstName and ID -> id.
+func graphqlFieldName(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// batchLoader collects the keys of records that will be needed to resolve a query, and loads all of them with a
+// single query the first time that any of them is requested. Parent resolvers prime the loader with the keys of all
+// the records they returned, which prevents the per-row queries that a naive resolver would execute.
+type batchLoader[V any] struct {
+	mu      sync.Mutex
+	fetch   func(keys []string) (map[string]V, error)
+	pending map[string]struct{}
+	loaded  map[string]V
+}
+
+func newBatchLoader[V any](fetch func(keys []string) (map[string]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{fetch: fetch, pending: map[string]struct{}{}, loaded: map[string]V{}}
+}
+
+// Prime registers keys that are likely to be loaded later.
+func (l *batchLoader[V]) Prime(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := l.loaded[key]; !ok {
+			l.pending[key] = struct{}{}
+		}
+	}
+}
+
+// Load returns the value for the given key, and fetches it together with all pending keys if it has not yet been
+// loaded. Keys that do not exist in the database are returned as the zero value of V.
+func (l *batchLoader[V]) Load(key string) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if v, ok := l.loaded[key]; ok {
+		return v, nil
+	}
+	l.pending[key] = struct{}{}
+	keys := make([]string, 0, len(l.pending))
+	for k := range l.pending {
+		keys = append(keys, k)
+	}
+	l.pending = map[string]struct{}{}
+	values, err := l.fetch(keys)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	for _, k := range keys {
+		l.loaded[k] = values[k]
+	}
+	return l.loaded[key], nil
+}
+
+// graphqlLoaders holds the batch loaders of one GraphQL request.
+type graphqlLoaders struct {
+	repo             MusicRepository
+	singers          *batchLoader[*Singer]
+	albums           *batchLoader[*Album]
+	venues           *batchLoader[*Venue]
+	albumsBySinger   *batchLoader[[]*Album]
+	concertsBySinger *batchLoader[[]*Concert]
+	concertsByVenue  *batchLoader[[]*Concert]
+	tracksByAlbum    *batchLoader[[]*Track]
+}
+
+type graphqlLoadersKey struct{}
+
+func withGraphqlLoaders(ctx context.Context, repo MusicRepository) context.Context {
+	l := &graphqlLoaders{repo: repo}
+	l.singers = newBatchLoader(func(ids []string) (map[string]*Singer, error) {
+		singers, err := repo.SingersByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		l.primeSingers(singers)
+		return byKey(singers, func(s *Singer) string { return s.ID }), nil
+	})
+	l.albums = newBatchLoader(func(ids []string) (map[string]*Album, error) {
+		albums, err := repo.AlbumsByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		l.primeAlbums(albums)
+		return byKey(albums, func(a *Album) string { return a.ID }), nil
+	})
+	l.venues = newBatchLoader(func(ids []string) (map[string]*Venue, error) {
+		venues, err := repo.VenuesByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		l.primeVenues(venues)
+		return byKey(venues, func(v *Venue) string { return v.ID }), nil
+	})
+	l.albumsBySinger = newBatchLoader(func(singerIds []string) (map[string][]*Album, error) {
+		albums, err := repo.AlbumsBySingerIds(ctx, singerIds)
+		if err != nil {
+			return nil, err
+		}
+		l.primeAlbums(albums)
+		return groupByKey(singerIds, albums, func(a *Album) string { return a.SingerId }), nil
+	})
+	l.concertsBySinger = newBatchLoader(func(singerIds []string) (map[string][]*Concert, error) {
+		concerts, err := repo.ConcertsBySingerIds(ctx, singerIds)
+		if err != nil {
+			return nil, err
+		}
+		l.primeConcerts(concerts)
+		return groupByKey(singerIds, concerts, func(c *Concert) string { return c.SingerId }), nil
+	})
+	l.concertsByVenue = newBatchLoader(func(venueIds []string) (map[string][]*Concert, error) {
+		concerts, err := repo.ConcertsByVenueIds(ctx, venueIds)
+		if err != nil {
+			return nil, err
+		}
+		l.primeConcerts(concerts)
+		return groupByKey(venueIds, concerts, func(c *Concert) string { return c.VenueId }), nil
+	})
+	l.tracksByAlbum = newBatchLoader(func(albumIds []string) (map[string][]*Track, error) {
+		tracks, err := repo.TracksByAlbumIds(ctx, albumIds)
+		if err != nil {
+			return nil, err
+		}
+		return groupByKey(albumIds, tracks, func(t *Track) string { return t.ID }), nil
+	})
+	return context.WithValue(ctx, graphqlLoadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	l, ok := ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+	if !ok {
+		panic(errors.New("graphql loaders missing from context"))
+	}
+	return l
+}
+
+func (l *graphqlLoaders) primeSingers(singers []*Singer) {
+	for _, s := range singers {
+		l.albumsBySinger.Prime(s.ID)
+		l.concertsBySinger.Prime(s.ID)
+	}
+}
+
+func (l *graphqlLoaders) primeAlbums(albums []*Album) {
+	for _, a := range albums {
+		l.singers.Prime(a.SingerId)
+		l.tracksByAlbum.Prime(a.ID)
+	}
+}
+
+func (l *graphqlLoaders) primeVenues(venues []*Venue) {
+	for _, v := range venues {
+		l.concertsByVenue.Prime(v.ID)
+	}
+}
+
+func (l *graphqlLoaders) primeConcerts(concerts []*Concert) {
+	for _, c := range concerts {
+		l.venues.Prime(c.VenueId)
+		l.singers.Prime(c.SingerId)
+	}
+}
+
+func byKey[V any](values []V, key func(V) string) map[string]V {
+	res := make(map[string]V, len(values))
+	for _, v := range values {
+		res[key(v)] = v
+	}
+	return res
+}
+
+// groupByKey groups the values by key. Each of the given keys is included in the result, so that records without
+// any associated values resolve to an empty list instead of null.
+func groupByKey[V any](keys []string, values []V, key func(V) string) map[string][]V {
+	res := make(map[string][]V, len(keys))
+	for _, k := range keys {
+		res[k] = []V{}
+	}
+	for _, v := range values {
+		res[key(v)] = append(res[key(v)], v)
+	}
+	return res
+}
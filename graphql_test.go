@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGraphqlPageIsClamped(t *testing.T) {
+	for _, tc := range []struct {
+		limit, offset int
+		want          Page
+	}{
+		{defaultConcertLimit, 10, Page{Limit: defaultConcertLimit, Offset: 10}},
+		{maxConcertLimit + 1, 0, Page{Limit: maxConcertLimit}},
+		{0, -1, Page{Limit: 1}},
+	} {
+		if got := graphqlPage(map[string]interface{}{"limit": tc.limit, "offset": tc.offset}); got != tc.want {
+			t.Errorf("limit %d, offset %d: got %+v, want %+v", tc.limit, tc.offset, got, tc.want)
+		}
+	}
+}
+
+func TestGraphqlMutationsRequirePost(t *testing.T) {
+	m := MusicDbOperation{repo: seedMemoryRepository(t), cfg: defaultConfig()}
+	gqlSchema, err := m.newGraphqlSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := m.graphqlHandler(gqlSchema)
+	mutation := `mutation { createSinger(lastName: "Jones") { id } }`
+	for _, tc := range []struct {
+		name, query, operationName string
+		want                       int
+	}{
+		{"query", `{ singer(id: "s1") { lastName } }`, "", http.StatusOK},
+		{"mutation", mutation, "", http.StatusMethodNotAllowed},
+		{"selected mutation", `query Find { singers { id } } mutation Create { createSinger(lastName: "Jones") { id } }`, "Create", http.StatusMethodNotAllowed},
+		{"selected query", `query Find { singers { id } } mutation Create { createSinger(lastName: "Jones") { id } }`, "Find", http.StatusOK},
+	} {
+		params := url.Values{"query": {tc.query}, "operationName": {tc.operationName}}
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil))
+		if w.Code != tc.want {
+			t.Errorf("%s: got status %d, %s, want %d", tc.name, w.Code, w.Body, tc.want)
+		}
+	}
+	if singers, _ := m.repo.ListSingers(context.Background(), Page{Limit: 10}); len(singers) != 1 {
+		t.Errorf("got %d singers after the GET requests, want 1", len(singers))
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": `+strconv.Quote(mutation)+`}`))
+	handler(w, r.WithContext(withIdentity(r.Context(), anonymous)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"createSinger":{"id"`) {
+		t.Errorf("POST: got status %d, %s", w.Code, w.Body)
+	}
+}
+
+func TestBatchLoaderFetchesPendingKeysOnce(t *testing.T) {
+	var fetched [][]string
+	l := newBatchLoader(func(keys []string) (map[string]string, error) {
+		sort.Strings(keys)
+		fetched = append(fetched, keys)
+		return map[string]string{"a": "A", "b": "B"}, nil
+	})
+	l.Prime("a", "b", "c")
+	for _, tc := range []struct{ key, want string }{{"a", "A"}, {"b", "B"}, {"c", ""}, {"a", "A"}} {
+		if got, err := l.Load(tc.key); err != nil || got != tc.want {
+			t.Errorf("Load(%q) = %q, %v, want %q", tc.key, got, err, tc.want)
+		}
+	}
+	// Keys that are primed after they were loaded are not fetched again.
+	l.Prime("a")
+	if _, err := l.Load("d"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(fetched); got != "[[a b c] [d]]" {
+		t.Errorf("got fetches %s, want [[a b c] [d]]", got)
+	}
+}
+
+// countingRepository counts the calls of the repository methods that the GraphQL loaders use.
+type countingRepository struct {
+	MusicRepository
+	calls map[string]int
+}
+
+func (r *countingRepository) SingersByIds(ctx context.Context, ids []string) ([]*Singer, error) {
+	r.calls["SingersByIds"]++
+	return r.MusicRepository.SingersByIds(ctx, ids)
+}
+
+func (r *countingRepository) AlbumsBySingerIds(ctx context.Context, singerIds []string) ([]*Album, error) {
+	r.calls["AlbumsBySingerIds"]++
+	return r.MusicRepository.AlbumsBySingerIds(ctx, singerIds)
+}
+
+func (r *countingRepository) TracksByAlbumIds(ctx context.Context, albumIds []string) ([]*Track, error) {
+	r.calls["TracksByAlbumIds"]++
+	return r.MusicRepository.TracksByAlbumIds(ctx, albumIds)
+}
+
+func (r *countingRepository) ConcertsBySingerIds(ctx context.Context, singerIds []string) ([]*Concert, error) {
+	r.calls["ConcertsBySingerIds"]++
+	return r.MusicRepository.ConcertsBySingerIds(ctx, singerIds)
+}
+
+func (r *countingRepository) VenuesByIds(ctx context.Context, ids []string) ([]*Venue, error) {
+	r.calls["VenuesByIds"]++
+	return r.MusicRepository.VenuesByIds(ctx, ids)
+}
+
+func TestGraphqlLoadersBatchAssociations(t *testing.T) {
+	ctx := context.Background()
+	mem := seedMemoryRepository(t)
+	for i := 2; i <= 5; i++ {
+		id := fmt.Sprintf("s%d", i)
+		for _, err := range []error{
+			mem.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: id}, LastName: "Singer " + id}),
+			mem.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a" + id}, Title: "Album", SingerId: id}),
+			mem.CreateTracks(ctx, []*Track{{BaseModel: BaseModel{ID: "a" + id}, TrackNumber: 1}}),
+		} {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	repo := &countingRepository{MusicRepository: mem, calls: map[string]int{}}
+	m := MusicDbOperation{repo: repo, cfg: defaultConfig()}
+	gqlSchema, err := m.newGraphqlSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := `{ singers { id albums { id singer { id } tracks { trackNumber } } concerts { venue { name } } } }`
+	w := httptest.NewRecorder()
+	m.graphqlHandler(gqlSchema)(w, httptest.NewRequest(http.MethodGet, "/graphql?"+url.Values{"query": {query}}.Encode(), nil))
+	var res struct {
+		Data struct {
+			Singers []struct {
+				Albums []struct {
+					Singer struct{ ID string }
+					Tracks []struct{ TrackNumber int }
+				}
+				Concerts []struct{ Venue struct{ Name string } }
+			}
+		}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || len(res.Errors) > 0 || len(res.Data.Singers) != 5 {
+		t.Fatalf("got status %d, %s", w.Code, w.Body)
+	}
+	tracks := 0
+	for _, singer := range res.Data.Singers {
+		for _, album := range singer.Albums {
+			tracks += len(album.Tracks)
+			if album.Singer.ID == "" {
+				t.Errorf("got album %+v without its singer", album)
+			}
+		}
+	}
+	if tracks != 6 {
+		t.Errorf("got %d tracks, want 6", tracks)
+	}
+	// Each association is loaded with one query for all singers, albums and concerts.
+	want := map[string]int{"AlbumsBySingerIds": 1, "TracksByAlbumIds": 1, "ConcertsBySingerIds": 1, "VenuesByIds": 1, "SingersByIds": 1}
+	if fmt.Sprint(repo.calls) != fmt.Sprint(want) {
+		t.Errorf("got calls %v, want %v", repo.calls, want)
+	}
+}
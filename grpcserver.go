@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/musicpb"
+)
+
+// musicGrpcServer implements the gRPC MusicService on top of the same MusicDbOperation logic as the HTTP routes.
+type musicGrpcServer struct {
+	musicpb.UnimplementedMusicServiceServer
+	m MusicDbOperation
+}
+
+func newGrpcServer(m MusicDbOperation, authn *auth, limiter *rateLimiter) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(authn.unaryInterceptor, limiter.unaryInterceptor, m.tenants.unaryInterceptor))
+	musicpb.RegisterMusicServiceServer(s, &musicGrpcServer{m: m})
+	// Register the reflection service, so tools like grpcurl can be used without the proto files.
+	reflection.Register(s)
+	return s
+}
+
+func (s *musicGrpcServer) RegisterSingerWithAlbum(ctx context.Context, req *musicpb.RegisterSingerWithAlbumRequest) (*musicpb.RegisterSingerWithAlbumResponse, error) {
+	singerId, albumId, err := s.m.registerSingerWithAlbum(ctx, req.GetFirstName(), req.GetLastName(), req.GetAlbumName())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &musicpb.RegisterSingerWithAlbumResponse{SingerId: singerId, AlbumId: albumId}, nil
+}
+
+func (s *musicGrpcServer) GetAlbumsOfSinger(ctx context.Context, req *musicpb.GetAlbumsOfSingerRequest) (*musicpb.GetAlbumsOfSingerResponse, error) {
+	albums, err := s.m.repoFor(ctx).AlbumsOfSinger(ctx, req.GetSingerId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if len(albums) == 0 {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	res := &musicpb.GetAlbumsOfSingerResponse{}
+	for _, album := range albums {
+		res.Albums = append(res.Albums, albumToProto(album))
+	}
+	return res, nil
+}
+
+func (s *musicGrpcServer) ListConcerts(ctx context.Context, req *musicpb.ListConcertsRequest) (*musicpb.ListConcertsResponse, error) {
+	filter := ConcertFilter{
+		VenueId:  req.GetVenueId(),
+		SingerId: req.GetSingerId(),
+		Limit:    int(req.GetLimit()),
+		Offset:   int(req.GetOffset()),
+	}
+	if req.From != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.To != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = defaultConcertLimit
+	case filter.Limit < 0 || filter.Limit > maxConcertLimit:
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxConcertLimit)
+	}
+	if filter.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must be a non-negative number")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return nil, status.Error(codes.InvalidArgument, "to must be after from")
+	}
+	concerts, err := s.m.repoFor(ctx).FindConcerts(ctx, filter, true)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &musicpb.ListConcertsResponse{}
+	for _, concert := range concerts {
+		res.Concerts = append(res.Concerts, concertToProto(concert))
+	}
+	return res, nil
+}
+
+func (s *musicGrpcServer) GetSingerStats(ctx context.Context, _ *musicpb.GetSingerStatsRequest) (*musicpb.GetSingerStatsResponse, error) {
+	stats, err := s.m.repoFor(ctx).SingerStats(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &musicpb.GetSingerStatsResponse{}
+	for _, st := range stats {
+		res.Singers = append(res.Singers, &musicpb.SingerStats{
+			SingerId:               st.SingerId,
+			FullName:               st.FullName,
+			AlbumCount:             st.AlbumCount,
+			TrackCount:             st.TrackCount,
+			TotalMarketingBudget:   decimalToProto(st.TotalBudget),
+			AverageMarketingBudget: decimalToProto(st.AverageBudget),
+		})
+	}
+	return res, nil
+}
+
+func (s *musicGrpcServer) GetSampleRateStats(ctx context.Context, req *musicpb.GetSampleRateStatsRequest) (*musicpb.SampleRateStats, error) {
+	bucketWidth := req.GetBucketWidth()
+	if bucketWidth < 0 {
+		return nil, status.Error(codes.InvalidArgument, "bucket_width must be a positive number")
+	}
+	if bucketWidth == 0 {
+		bucketWidth = defaultSampleRateBucketWidth
+	}
+	stats, err := s.m.repoFor(ctx).SampleRateStats(ctx, bucketWidth)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &musicpb.SampleRateStats{
+		TrackCount:  stats.TrackCount,
+		Average:     stats.Average,
+		Min:         stats.Min,
+		Max:         stats.Max,
+		BucketWidth: stats.BucketWidth,
+	}
+	for _, b := range stats.Buckets {
+		res.Buckets = append(res.Buckets, &musicpb.SampleRateStats_Bucket{LowerBound: b.LowerBound, TrackCount: b.TrackCount})
+	}
+	return res, nil
+}
+
+func (s *musicGrpcServer) GetAlbumsPerDecade(ctx context.Context, _ *musicpb.GetAlbumsPerDecadeRequest) (*musicpb.GetAlbumsPerDecadeResponse, error) {
+	stats, err := s.m.repoFor(ctx).AlbumsPerDecade(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &musicpb.GetAlbumsPerDecadeResponse{}
+	for _, st := range stats {
+		res.Decades = append(res.Decades, &musicpb.DecadeStats{Decade: st.Decade, AlbumCount: st.AlbumCount})
+	}
+	return res, nil
+}
+
+// grpcError converts an error that was returned by the database to a gRPC status.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func decimalToProto(d decimal.NullDecimal) *string {
+	if !d.Valid {
+		return nil
+	}
+	return proto.String(d.Decimal.String())
+}
+
+func singerToProto(singer *Singer) *musicpb.Singer {
+	if singer == nil || singer.ID == "" {
+		return nil
+	}
+	res := &musicpb.Singer{
+		Id:        singer.ID,
+		LastName:  singer.LastName,
+		FullName:  singer.FullName,
+		Active:    singer.Active,
+		CreatedAt: timeToProto(singer.CreatedAt),
+		UpdatedAt: timeToProto(singer.UpdatedAt),
+	}
+	if singer.FirstName.Valid {
+		res.FirstName = proto.String(singer.FirstName.String)
+	}
+	for i := range singer.Albums {
+		res.Albums = append(res.Albums, albumToProto(&singer.Albums[i]))
+	}
+	return res
+}
+
+func albumToProto(album *Album) *musicpb.Album {
+	res := &musicpb.Album{
+		Id:              album.ID,
+		Title:           album.Title,
+		MarketingBudget: decimalToProto(album.MarketingBudget),
+		CoverPicture:    album.CoverPicture,
+		SingerId:        album.SingerId,
+		Singer:          singerToProto(&album.Singer),
+		CreatedAt:       timeToProto(album.CreatedAt),
+		UpdatedAt:       timeToProto(album.UpdatedAt),
+	}
+	if releaseDate := time.Time(album.ReleaseDate); !releaseDate.IsZero() {
+		res.ReleaseDate = releaseDate.Format("2006-01-02")
+	}
+	for _, track := range album.Tracks {
+		res.Tracks = append(res.Tracks, &musicpb.Track{
+			Id:          track.ID,
+			TrackNumber: track.TrackNumber,
+			Title:       track.Title,
+			SampleRate:  track.SampleRate,
+			CreatedAt:   timeToProto(track.CreatedAt),
+			UpdatedAt:   timeToProto(track.UpdatedAt),
+		})
+	}
+	return res
+}
+
+func venueToProto(venue *Venue) *musicpb.Venue {
+	if venue == nil || venue.ID == "" {
+		return nil
+	}
+	return &musicpb.Venue{
+		Id:          venue.ID,
+		Name:        venue.Name,
+		Description: venue.Description,
+		CreatedAt:   timeToProto(venue.CreatedAt),
+		UpdatedAt:   timeToProto(venue.UpdatedAt),
+	}
+}
+
+func concertToProto(concert *Concert) *musicpb.Concert {
+	return &musicpb.Concert{
+		Id:        concert.ID,
+		Name:      concert.Name,
+		VenueId:   concert.VenueId,
+		Venue:     venueToProto(&concert.Venue),
+		SingerId:  concert.SingerId,
+		Singer:    singerToProto(&concert.Singer),
+		StartTime: timeToProto(concert.StartTime),
+		EndTime:   timeToProto(concert.EndTime),
+		CreatedAt: timeToProto(concert.CreatedAt),
+		UpdatedAt: timeToProto(concert.UpdatedAt),
+	}
+}
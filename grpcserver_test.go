@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/musicpb"
+)
+
+// newGrpcTestClient serves the gRPC API with the memory repository on an in-memory listener. ci-key authenticates
+// an editor, and reader-key a reader. Each caller may register one singer.
+func newGrpcTestClient(t *testing.T) musicpb.MusicServiceClient {
+	t.Helper()
+	cfg := defaultConfig()
+	cfg.AuthEnabled, cfg.APIKeys = true, []string{"ci:editor:ci-key", "reader:reader:reader-key"}
+	cfg.WriteRateLimit, cfg.WriteBurst = 0.001, 1
+	cfg.Tenants = map[string]TenantConfig{"label1": {DatabaseName: "label1", Subjects: []string{"label1-ci"}}}
+	authn, err := newAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := MusicDbOperation{repo: newMemoryRepository(), cfg: cfg, state: &serverState{}, tenants: newTenantRouter(cfg, zerolog.Nop())}
+	server := newGrpcServer(m, authn, newRateLimiter(cfg))
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return musicpb.NewMusicServiceClient(conn)
+}
+
+func withAPIKey(key string, pairs ...string) context.Context {
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(append([]string{"x-api-key", key}, pairs...)...))
+}
+
+func TestGrpcRegisterAndGetAlbums(t *testing.T) {
+	client := newGrpcTestClient(t)
+	ctx := withAPIKey("ci-key")
+	ids, err := client.RegisterSingerWithAlbum(ctx, &musicpb.RegisterSingerWithAlbumRequest{
+		FirstName: "Alice", LastName: "Jones", AlbumName: "Hits"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.GetAlbumsOfSinger(ctx, &musicpb.GetAlbumsOfSingerRequest{SingerId: ids.GetSingerId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetAlbums()) != 1 || res.GetAlbums()[0].GetId() != ids.GetAlbumId() || len(res.GetAlbums()[0].GetTracks()) == 0 {
+		t.Errorf("got albums %v, want album %s with its tracks", res.GetAlbums(), ids.GetAlbumId())
+	}
+}
+
+func TestGrpcErrors(t *testing.T) {
+	client := newGrpcTestClient(t)
+	for _, tc := range []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"unknown singer", func() error {
+			_, err := client.GetAlbumsOfSinger(withAPIKey("ci-key"), &musicpb.GetAlbumsOfSingerRequest{SingerId: "s2"})
+			return err
+		}, codes.NotFound},
+		{"invalid limit", func() error {
+			_, err := client.ListConcerts(withAPIKey("ci-key"), &musicpb.ListConcertsRequest{Limit: maxConcertLimit + 1})
+			return err
+		}, codes.InvalidArgument},
+		// The interceptors authenticate the caller, then check the rate limit and then the tenant.
+		{"no credentials", func() error {
+			_, err := client.ListConcerts(context.Background(), &musicpb.ListConcertsRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"reader registers", func() error {
+			_, err := client.RegisterSingerWithAlbum(withAPIKey("reader-key"), &musicpb.RegisterSingerWithAlbumRequest{LastName: "Jones"})
+			return err
+		}, codes.PermissionDenied},
+		{"first write", func() error {
+			_, err := client.RegisterSingerWithAlbum(withAPIKey("ci-key"), &musicpb.RegisterSingerWithAlbumRequest{LastName: "Jones"})
+			return err
+		}, codes.OK},
+		{"second write", func() error {
+			_, err := client.RegisterSingerWithAlbum(withAPIKey("ci-key"), &musicpb.RegisterSingerWithAlbumRequest{LastName: "Jones"})
+			return err
+		}, codes.ResourceExhausted},
+		{"write with other tenant", func() error {
+			_, err := client.RegisterSingerWithAlbum(withAPIKey("ci-key", "x-tenant", "label1"), &musicpb.RegisterSingerWithAlbumRequest{LastName: "Jones"})
+			return err
+		}, codes.ResourceExhausted},
+		{"read with other tenant", func() error {
+			_, err := client.ListConcerts(withAPIKey("ci-key", "x-tenant", "label1"), &musicpb.ListConcertsRequest{})
+			return err
+		}, codes.PermissionDenied},
+	} {
+		if got := status.Code(tc.call()); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+	"gorm.io/gorm"
+)
+
+// HealthCheck is the result of one check of a health report.
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport is the response body of /healthz and /readyz.
+type HealthReport struct {
+	Status string         `json:"status"`
+	Checks []*HealthCheck `json:"checks"`
+}
+
+// healthModels are the models whose tables must exist before the server can handle requests.
+var healthModels = []interface{}{&Singer{}, &Album{}, &Track{}, &Venue{}, &Concert{}}
+
+// healthz is the liveness probe. It only reports whether the process can handle HTTP requests, so that an
+// unreachable database makes the instance unready instead of causing it to be restarted.
+func (m MusicDbOperation) healthz(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, HealthReport{Status: "ok", Checks: []*HealthCheck{}})
+}
+
+// readyz is the readiness probe. It reports unavailable as soon as the server starts to drain, so that load balancers
+// stop sending new requests before the listeners are closed, and when the database cannot be reached through PGAdapter.
+func (m MusicDbOperation) readyz(w http.ResponseWriter, r *http.Request) {
+	report := HealthReport{Status: "ready"}
+	if m.state.isDraining() {
+		report.Checks = []*HealthCheck{{Name: "serving", Status: "fail", Error: "server is shutting down"}}
+	} else {
+		report.Checks = []*HealthCheck{
+			m.runHealthCheck(r.Context(), "ping", m.pingDb),
+			m.runHealthCheck(r.Context(), "select", m.selectOne),
+			m.runHealthCheck(r.Context(), "schema", m.checkTables),
+		}
+	}
+	for _, check := range report.Checks {
+		if check.Status != "ok" {
+			report.Status = "unavailable"
+			render.Status(r, http.StatusServiceUnavailable)
+		}
+	}
+	render.JSON(w, r, report)
+}
+
+// runHealthCheck runs check with the configured health check timeout and measures its latency.
+func (m MusicDbOperation) runHealthCheck(ctx context.Context, name string, check func(ctx context.Context) error) *HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := &HealthCheck{
+		Name:      name,
+		Status:    "ok",
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// pingDb checks that a connection to PGAdapter can be established.
+func (m MusicDbOperation) pingDb(ctx context.Context) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// selectOne checks that PGAdapter can execute a query on Cloud Spanner.
+func (m MusicDbOperation) selectOne(ctx context.Context) error {
+	var one int64
+	if err := m.db.WithContext(ctx).Raw("SELECT 1").Scan(&one).Error; err != nil {
+		return err
+	}
+	if one != 1 {
+		return fmt.Errorf("SELECT 1 returned %d", one)
+	}
+	return nil
+}
+
+// checkTables checks that the tables of the data model exist.
+func (m MusicDbOperation) checkTables(ctx context.Context) error {
+	expected := make([]string, 0, len(healthModels))
+	for _, model := range healthModels {
+		stmt := &gorm.Statement{DB: m.db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		expected = append(expected, stmt.Schema.Table)
+	}
+
+	var found []string
+	if err := m.db.WithContext(ctx).Raw("SELECT table_name FROM information_schema.tables "+
+		"WHERE table_schema = 'public' AND table_name IN ?", expected).Scan(&found).Error; err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(found))
+	for _, table := range found {
+		existing[table] = true
+	}
+	var missing []string
+	for _, table := range expected {
+		if !existing[table] {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("missing tables: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+// getReadyz calls the readiness probe and returns its status and report.
+func getReadyz(t *testing.T, m MusicDbOperation) (int, HealthReport) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	m.readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report HealthReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid report %s: %v", w.Body, err)
+	}
+	return w.Code, report
+}
+
+// failedChecks returns the names of the checks of report that failed.
+func failedChecks(report HealthReport) []string {
+	var failed []string
+	for _, check := range report.Checks {
+		if check.Status != "ok" {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+func TestReadyz(t *testing.T) {
+	server, db := newFakeDb(t)
+	cfg := defaultConfig()
+	m := MusicDbOperation{db: db, repo: newGormRepository(db), cfg: cfg, state: &serverState{}, tenants: newTenantRouter(cfg, zerolog.Nop())}
+	server.On("SELECT 1", pgfake.Rows([]pgfake.Column{{Name: "?column?", OID: pgtype.Int8OID}}, []interface{}{1}))
+	tables := pgfake.Rows([]pgfake.Column{{Name: "table_name", OID: pgtype.TextOID}},
+		[]interface{}{"singers"}, []interface{}{"albums"}, []interface{}{"tracks"}, []interface{}{"venues"},
+		[]interface{}{"concerts"})
+	server.On("information_schema.tables", tables)
+
+	code, report := getReadyz(t, m)
+	if code != http.StatusOK || report.Status != "ready" || len(report.Checks) != 3 || len(failedChecks(report)) != 0 {
+		t.Errorf("got status %d, %+v, want ready", code, report)
+	}
+
+	// A missing table and a failing schema query make the server unready.
+	server.On("information_schema.tables", pgfake.Rows([]pgfake.Column{{Name: "table_name", OID: pgtype.TextOID}},
+		[]interface{}{"singers"}, []interface{}{"albums"}))
+	code, report = getReadyz(t, m)
+	if failed := failedChecks(report); code != http.StatusServiceUnavailable || len(failed) != 1 || failed[0] != "schema" ||
+		report.Checks[2].Error != "missing tables: tracks, venues, concerts" {
+		t.Errorf("got status %d, %+v, want the missing tables", code, report)
+	}
+	server.On("information_schema.tables", pgfake.Error("42P01", "Table not found: information_schema.tables"))
+	code, report = getReadyz(t, m)
+	if failed := failedChecks(report); code != http.StatusServiceUnavailable || report.Status != "unavailable" ||
+		len(failed) != 1 || failed[0] != "schema" {
+		t.Errorf("got status %d, %+v, want a failed schema check", code, report)
+	}
+
+	// A draining server is unready without querying the database.
+	server.Reset()
+	m.state.startDraining()
+	code, report = getReadyz(t, m)
+	if failed := failedChecks(report); code != http.StatusServiceUnavailable || len(failed) != 1 || failed[0] != "serving" {
+		t.Errorf("got status %d, %+v, want a failed serving check", code, report)
+	}
+	if got := len(server.Statements()); got != 0 {
+		t.Errorf("got %d statements while draining, want none", got)
+	}
+}
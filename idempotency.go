@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httplog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/client"
+)
+
+// maxIdempotencyKeyLength limits the length of the Idempotency-Key header. The client sends UUIDs.
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodySize limits the size of the bodies of requests with an idempotency key, which are read into memory
+// to compare them with the request that first used the key.
+const maxIdempotentBodySize = 1 << 20
+
+var idempotentRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "idempotent_requests_total",
+	Help: "Number of requests with an idempotency key by result: executed, replayed, in_progress or mismatch.",
+}, []string{"result"})
+
+// idempotencyStore remembers the responses of POST requests with an Idempotency-Key header, so that a client can
+// retry a request without creating the singer and album twice. The responses are kept in memory for the configured
+// TTL, so a retry that reaches another instance, or the same instance after a restart, is executed again.
+type idempotencyStore struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	responses map[string]*idempotentResponse
+	lastPurge time.Time
+}
+
+// idempotentResponse is the response of the first request with a key. done is false while the request is executed.
+type idempotentResponse struct {
+	requestHash [sha256.Size]byte
+	expires     time.Time
+	done        bool
+	status      int
+	contentType string
+	body        []byte
+}
+
+func newIdempotencyStore(cfg *Config) *idempotencyStore {
+	return &idempotencyStore{ttl: cfg.IdempotencyTTL, responses: map[string]*idempotentResponse{}}
+}
+
+// start returns the stored response of the key, or stores a new response that is in progress and returns nil.
+func (s *idempotencyStore) start(key string, requestHash [sha256.Size]byte, now time.Time) *idempotentResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastPurge) > time.Minute {
+		for k, resp := range s.responses {
+			if resp.done && now.After(resp.expires) {
+				delete(s.responses, k)
+			}
+		}
+		s.lastPurge = now
+	}
+	if resp, ok := s.responses[key]; ok && (!resp.done || now.Before(resp.expires)) {
+		return resp
+	}
+	s.responses[key] = &idempotentResponse{requestHash: requestHash}
+	return nil
+}
+
+// finish stores the response of the request with the key. Server errors are not stored, so that the request is
+// executed again when it is retried.
+func (s *idempotencyStore) finish(key string, status int, contentType string, body []byte, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, ok := s.responses[key]
+	if !ok {
+		return
+	}
+	if status >= http.StatusInternalServerError {
+		delete(s.responses, key)
+		return
+	}
+	resp.done, resp.status, resp.contentType, resp.body, resp.expires = true, status, contentType, body, now.Add(s.ttl)
+}
+
+// middleware executes POST requests with an Idempotency-Key header at most once per key, caller and tenant, and
+// returns the stored response for later requests with the same key. A request with a key that is still in progress
+// is rejected with 409, and a request with a key that was used for another path or body with 422.
+func (s *idempotencyStore) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(client.IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || header == "" || s.ttl <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(header) > maxIdempotencyKeyLength {
+			errorRender(w, r, http.StatusBadRequest,
+				fmt.Errorf("%s must not be longer than %d characters", client.IdempotencyKeyHeader, maxIdempotencyKeyLength))
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			if len(body) >= maxIdempotentBodySize {
+				errorRender(w, r, http.StatusRequestEntityTooLarge,
+					fmt.Errorf("request bodies with an idempotency key must not be larger than %d bytes", maxIdempotentBodySize))
+				return
+			}
+			errorRender(w, r, http.StatusBadRequest, fmt.Errorf("could not read the request body: %w", err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		caller := ""
+		if id := identityFrom(r.Context()); id != nil && id != anonymous {
+			caller = id.Subject
+		}
+		key := tenantName(r.Context()) + "\x00" + caller + "\x00" + header
+		requestHash := sha256.Sum256(append([]byte(r.URL.Path+"\x00"), body...))
+		stored := s.start(key, requestHash, time.Now())
+		switch {
+		case stored == nil:
+		case stored.requestHash != requestHash:
+			idempotentRequests.WithLabelValues("mismatch").Inc()
+			errorRender(w, r, http.StatusUnprocessableEntity,
+				errors.New("the idempotency key was already used for a different request"))
+			return
+		case !stored.done:
+			idempotentRequests.WithLabelValues("in_progress").Inc()
+			w.Header().Set("Retry-After", "1")
+			errorRender(w, r, http.StatusConflict, errors.New("a request with the same idempotency key is in progress"))
+			return
+		default:
+			idempotentRequests.WithLabelValues("replayed").Inc()
+			// Other headers, such as the rate limit headers, belong to the current request.
+			w.Header().Set("Content-Type", stored.contentType)
+			w.Header().Set(client.IdempotentReplayedHeader, "true")
+			w.WriteHeader(stored.status)
+			if _, err := w.Write(stored.body); err != nil {
+				oplog := httplog.LogEntry(r.Context())
+				oplog.Error().Err(err).Msg("failed to write the stored response")
+			}
+			return
+		}
+
+		idempotentRequests.WithLabelValues("executed").Inc()
+		status := http.StatusInternalServerError
+		var response bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&response)
+		// A panic of the handler releases the key with the status 500.
+		defer func() {
+			s.finish(key, status, ww.Header().Get("Content-Type"), response.Bytes(), time.Now())
+		}()
+		next.ServeHTTP(ww, r)
+		if status = ww.Status(); status == 0 {
+			status = http.StatusOK
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/client"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	store := newIdempotencyStore(defaultConfig())
+	executed := 0
+	status := http.StatusInternalServerError
+	var inHandler func()
+	handler := store.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executed++
+		if inHandler != nil {
+			inHandler()
+		}
+		errorRender(w, r, status, http.ErrAbortHandler)
+	}))
+	body := `{}`
+	post := func(key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/register-singer-with-album", strings.NewReader(body))
+		r.Header.Set(client.IdempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// A request with the same key is rejected while the first one is in progress.
+	inHandler = func() {
+		if w := post("k1"); w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+			t.Errorf("got status %d while the request is in progress, want 409 with Retry-After", w.Code)
+		}
+	}
+	post("k1")
+	inHandler = nil
+
+	// Server errors are not stored, so the retry is executed.
+	status = http.StatusBadRequest
+	if w := post("k1"); w.Code != http.StatusBadRequest || executed != 2 {
+		t.Fatalf("got status %d after %d executions, want 400 after 2", w.Code, executed)
+	}
+	w := post("k1")
+	if w.Code != http.StatusBadRequest || executed != 2 || w.Header().Get(client.IdempotentReplayedHeader) != "true" ||
+		!strings.Contains(w.Body.String(), "ERROR") {
+		t.Errorf("got status %d, headers %v after %d executions, want the replayed response", w.Code, w.Header(), executed)
+	}
+
+	// Bodies are only read up to the limit.
+	body = strings.Repeat(" ", maxIdempotentBodySize+1)
+	if w := post("k2"); w.Code != http.StatusRequestEntityTooLarge || executed != 2 {
+		t.Errorf("got status %d after %d executions for a large body, want 413 after 2", w.Code, executed)
+	}
+}
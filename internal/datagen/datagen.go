@@ -0,0 +1,219 @@
+// Package datagen generates random, but realistic data for the music catalog: names and titles from a vocabulary,
+// release dates, marketing budgets, track counts and sample rates. It is used by the sample, the initial data, the
+// handlers and the load test, and by tests that need reproducible data.
+//
+// A Generator is seeded, so that the same seed and vocabulary always generate the same data. A Generator is not safe
+// for concurrent use: each goroutine creates its own Generator, seeded with NewSeed unless it needs reproducible data.
+package datagen
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// Vocabulary contains the words of the generated names and titles. Album titles consist of an adjective and a noun,
+// and track titles of an adverb and a verb.
+type Vocabulary struct {
+	FirstNames []string `yaml:"first_names"`
+	LastNames  []string `yaml:"last_names"`
+	Adjectives []string `yaml:"adjectives"`
+	Nouns      []string `yaml:"nouns"`
+	Adverbs    []string `yaml:"adverbs"`
+	Verbs      []string `yaml:"verbs"`
+}
+
+//go:embed vocabulary.yaml
+var defaultVocabularyYAML []byte
+
+var (
+	defaultVocabularyOnce sync.Once
+	defaultVocab          *Vocabulary
+)
+
+func defaultVocabulary() *Vocabulary {
+	defaultVocabularyOnce.Do(func() {
+		defaultVocab = &Vocabulary{}
+		if err := yaml.Unmarshal(defaultVocabularyYAML, defaultVocab); err != nil {
+			panic(fmt.Sprintf("invalid default vocabulary: %v", err))
+		}
+	})
+	return defaultVocab
+}
+
+// DefaultVocabulary returns the vocabulary that is used if no vocabulary file is configured.
+func DefaultVocabulary() *Vocabulary {
+	return defaultVocabulary().clone()
+}
+
+// LoadVocabulary reads a vocabulary from a YAML file in the format of vocabulary.yaml. Lists that are missing in the
+// file are taken from the default vocabulary.
+func LoadVocabulary(path string) (*Vocabulary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read vocabulary: %w", err)
+	}
+	v := DefaultVocabulary()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	// Decoding replaces the lists that are in the file.
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid vocabulary %s: %w", path, err)
+	}
+	for name, words := range map[string][]string{
+		"first_names": v.FirstNames, "last_names": v.LastNames, "adjectives": v.Adjectives,
+		"nouns": v.Nouns, "adverbs": v.Adverbs, "verbs": v.Verbs,
+	} {
+		if len(words) == 0 {
+			return nil, fmt.Errorf("invalid vocabulary %s: %s must not be empty", path, name)
+		}
+	}
+	return v, nil
+}
+
+func (v *Vocabulary) clone() *Vocabulary {
+	return &Vocabulary{
+		FirstNames: append([]string{}, v.FirstNames...),
+		LastNames:  append([]string{}, v.LastNames...),
+		Adjectives: append([]string{}, v.Adjectives...),
+		Nouns:      append([]string{}, v.Nouns...),
+		Adverbs:    append([]string{}, v.Adverbs...),
+		Verbs:      append([]string{}, v.Verbs...),
+	}
+}
+
+var (
+	seedMu  sync.Mutex
+	seedRnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// NewSeed returns a random seed for a Generator. It is safe for concurrent use.
+func NewSeed() int64 {
+	seedMu.Lock()
+	defer seedMu.Unlock()
+	return seedRnd.Int63()
+}
+
+// Generator generates random data. It is not safe for concurrent use.
+type Generator struct {
+	rnd   *rand.Rand
+	vocab *Vocabulary
+}
+
+// New returns a Generator with the given seed that uses the words of vocab, or of the default vocabulary if vocab is
+// nil. vocab must not be modified while the Generator is used.
+func New(seed int64, vocab *Vocabulary) *Generator {
+	if vocab == nil {
+		vocab = defaultVocabulary()
+	}
+	return &Generator{rnd: rand.New(rand.NewSource(seed)), vocab: vocab}
+}
+
+// Intn returns a number in [0, n).
+func (g *Generator) Intn(n int) int {
+	return g.rnd.Intn(n)
+}
+
+// Between returns a number in [min, max].
+func (g *Generator) Between(min, max int) int {
+	return min + g.rnd.Intn(max-min+1)
+}
+
+func (g *Generator) pick(words []string) string {
+	return words[g.rnd.Intn(len(words))]
+}
+
+func (g *Generator) FirstName() string {
+	return g.pick(g.vocab.FirstNames)
+}
+
+func (g *Generator) LastName() string {
+	return g.pick(g.vocab.LastNames)
+}
+
+func (g *Generator) AlbumTitle() string {
+	return g.pick(g.vocab.Adjectives) + " " + g.pick(g.vocab.Nouns)
+}
+
+func (g *Generator) TrackTitle() string {
+	return g.pick(g.vocab.Adverbs) + " " + g.pick(g.vocab.Verbs)
+}
+
+// First and last year of the release dates.
+const (
+	firstReleaseYear = 1950
+	lastReleaseYear  = 2024
+)
+
+// ReleaseDate returns a date between 1950 and 2024. More recent years are more likely, as more albums are released
+// every year: the density increases linearly from 1950 to 2024.
+func (g *Generator) ReleaseDate() time.Time {
+	years := float64(lastReleaseYear - firstReleaseYear + 1)
+	year := firstReleaseYear + int(years*math.Sqrt(g.rnd.Float64()))
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	days := start.AddDate(1, 0, 0).Sub(start).Hours() / 24
+	return start.AddDate(0, 0, g.rnd.Intn(int(days)))
+}
+
+// MarketingBudget returns a budget with two decimals that follows a log-normal distribution with a median of 50,000
+// between 1,000 and 10,000,000, or no budget for 10% of the albums.
+func (g *Generator) MarketingBudget() decimal.NullDecimal {
+	if g.rnd.Float64() < 0.1 {
+		return decimal.NullDecimal{}
+	}
+	budget := math.Exp(math.Log(50000) + 1.2*g.rnd.NormFloat64())
+	budget = math.Min(math.Max(budget, 1000), 10000000)
+	return decimal.NullDecimal{Decimal: decimal.NewFromFloat(budget).Round(2), Valid: true}
+}
+
+// TrackCount returns the number of tracks of an album: normally distributed around 11 with a standard deviation of
+// 3, between 1 and 30.
+func (g *Generator) TrackCount() int {
+	n := int(math.Round(11 + 3*g.rnd.NormFloat64()))
+	if n < 1 {
+		return 1
+	}
+	if n > 30 {
+		return 30
+	}
+	return n
+}
+
+// sampleRates are the common sample rates in kHz with their relative weights.
+var sampleRates = []struct {
+	rate   float64
+	weight int
+}{{44.1, 60}, {48, 25}, {88.2, 3}, {96, 9}, {192, 3}}
+
+// SampleRate returns a common sample rate in kHz, mostly 44.1 (CD) or 48.
+func (g *Generator) SampleRate() float64 {
+	total := 0
+	for _, r := range sampleRates {
+		total += r.weight
+	}
+	n := g.rnd.Intn(total)
+	for _, r := range sampleRates {
+		if n < r.weight {
+			return r.rate
+		}
+		n -= r.weight
+	}
+	return sampleRates[0].rate
+}
+
+// CoverPicture returns between 5,000 and 15,000 random bytes.
+func (g *Generator) CoverPicture() []byte {
+	b := make([]byte, g.Between(5000, 15000))
+	g.rnd.Read(b)
+	return b
+}
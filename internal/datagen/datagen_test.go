@@ -0,0 +1,101 @@
+package datagen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sample generates some values of each kind.
+func sample(g *Generator) []interface{} {
+	var values []interface{}
+	for i := 0; i < 10; i++ {
+		values = append(values, g.FirstName(), g.LastName(), g.AlbumTitle(), g.TrackTitle(), g.ReleaseDate(),
+			g.MarketingBudget(), g.TrackCount(), g.SampleRate(), len(g.CoverPicture()))
+	}
+	return values
+}
+
+func TestSameSeedGeneratesSameData(t *testing.T) {
+	if a, b := sample(New(42, nil)), sample(New(42, nil)); !reflect.DeepEqual(a, b) {
+		t.Errorf("got different data for the same seed:\n%v\n%v", a, b)
+	}
+	if a, b := sample(New(1, nil)), sample(New(2, nil)); reflect.DeepEqual(a, b) {
+		t.Error("got the same data for different seeds")
+	}
+}
+
+func TestDistributions(t *testing.T) {
+	g := New(1, nil)
+	budgets, recent := 0, 0
+	for i := 0; i < 10000; i++ {
+		if n := g.TrackCount(); n < 1 || n > 30 {
+			t.Fatalf("got %d tracks", n)
+		}
+		if n := g.Between(5, 10); n < 5 || n > 10 {
+			t.Fatalf("got %d, want a number between 5 and 10", n)
+		}
+		date := g.ReleaseDate()
+		if date.Year() < firstReleaseYear || date.Year() > lastReleaseYear || date.Location() != time.UTC {
+			t.Fatalf("got release date %v", date)
+		}
+		if date.Year() >= 1987 {
+			recent++
+		}
+		if budget := g.MarketingBudget(); budget.Valid {
+			budgets++
+			if f, _ := budget.Decimal.Float64(); f < 1000 || f > 10000000 || budget.Decimal.Exponent() < -2 {
+				t.Fatalf("got budget %v", budget.Decimal)
+			}
+		}
+	}
+	// The second half of the years has three quarters of the albums, and 10% of the albums have no budget.
+	if recent < 7000 || recent > 8000 {
+		t.Errorf("got %d of 10000 albums released since 1987, want about 7500", recent)
+	}
+	if budgets < 8700 || budgets > 9300 {
+		t.Errorf("got %d of 10000 albums with a budget, want about 9000", budgets)
+	}
+}
+
+func TestLoadVocabulary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vocabulary.yaml")
+	if err := os.WriteFile(path, []byte("first_names: [Ada]\nlast_names: [Lovelace]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vocab, err := LoadVocabulary(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vocab.Nouns, DefaultVocabulary().Nouns) {
+		t.Errorf("got nouns %v, want the default nouns", vocab.Nouns)
+	}
+	g := New(1, vocab)
+	if name := g.FirstName() + " " + g.LastName(); name != "Ada Lovelace" {
+		t.Errorf("got name %q", name)
+	}
+
+	for _, content := range []string{"nouns: []\n", "colors: [red]\n"} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadVocabulary(path); err == nil {
+			t.Errorf("%q: got no error", content)
+		}
+	}
+}
+
+func TestGeneratorsPerGoroutine(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sample(New(NewSeed(), nil))
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,513 @@
+// Package pgfake is an in-process server that speaks the PostgreSQL wire protocol, so that tests can check the
+// statements that the application sends to PGAdapter without running PGAdapter or Cloud Spanner. The server records
+// all statements with their parameters and returns scripted results and errors.
+//
+// Parameters are described to the client without a type, so pgx sends all parameters in text format, and all
+// results are sent in text format.
+package pgfake
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+// Statement is a statement that was executed by a client.
+type Statement struct {
+	SQL string
+	// Params are the parameters of the statement: nil for NULL, a string for parameters in text format and a
+	// []byte for parameters in binary format.
+	Params []interface{}
+}
+
+// Column is a column of a scripted result. OID is the type of the column, such as pgtype.Int8OID.
+type Column struct {
+	Name string
+	OID  uint32
+}
+
+// Result is the scripted result of a statement.
+type Result struct {
+	Columns []Column
+	Rows    [][]interface{}
+	// Tag is the command tag, such as "UPDATE 1". If empty, it is derived from the statement and the rows.
+	Tag string
+	Err *pgproto3.ErrorResponse
+	// Disconnect closes the connection after the statement was received, like a restart of PGAdapter.
+	Disconnect bool
+}
+
+// Rows returns a result with the given columns and rows. Values are sent in text format: nil is NULL, strings and
+// byte slices are sent as is, times as timestamptz and all other values are formatted with fmt.
+func Rows(columns []Column, rows ...[]interface{}) Result {
+	return Result{Columns: columns, Rows: rows}
+}
+
+// Tag returns a result without rows with the given command tag, such as "DELETE 0".
+func Tag(tag string) Result {
+	return Result{Tag: tag}
+}
+
+// Error returns a result that fails with the given SQLSTATE and message.
+func Error(code, message string) Result {
+	return Result{Err: &pgproto3.ErrorResponse{Severity: "ERROR", Code: code, Message: message}}
+}
+
+// Disconnect returns a result that closes the connection instead of responding to the statement.
+func Disconnect() Result {
+	return Result{Disconnect: true}
+}
+
+// Aborted returns the error that PGAdapter returns if Cloud Spanner aborted the transaction.
+func Aborted() Result {
+	return Error("40001", "Transaction was aborted. It was wrapped in a retry loop and must be retried.")
+}
+
+// TooManyParametersCode is the SQLSTATE of the error for statements with more than MaxParams parameters.
+const TooManyParametersCode = "54023"
+
+type rule struct {
+	fragment string
+	results  []Result
+	used     int
+}
+
+// Server is a fake PostgreSQL server. Use NewServer to start one.
+type Server struct {
+	// MaxParams is the maximum number of parameters of a statement, 0 for unlimited. Statements with more
+	// parameters fail with TooManyParametersCode when they are prepared, like they do in PGAdapter.
+	MaxParams int
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu         sync.Mutex
+	rules      []*rule
+	statements []Statement
+	conns      map[net.Conn]struct{}
+	closed     bool
+}
+
+// NewServer starts a server on a random local port.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{listener: l, conns: map[net.Conn]struct{}{}}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// DSN returns the connection string of the server.
+func (s *Server) DSN() string {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return fmt.Sprintf("host=%s port=%d user=test database=test sslmode=disable", addr.IP, addr.Port)
+}
+
+// Close stops the server and closes all connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// On scripts the results of the statements that contain fragment. The results are returned in order for each
+// matching statement, and the last result is repeated. Rules that were added later take precedence.
+func (s *Server) On(fragment string, results ...Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = append(s.rules, &rule{fragment: fragment, results: results})
+}
+
+// Statements returns the statements that were executed so far.
+func (s *Server) Statements() []Statement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Statement(nil), s.statements...)
+}
+
+// Reset removes all recorded statements.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statements = nil
+}
+
+// result returns the result of sql. If consume is false, the result is only looked up, so that a Describe returns
+// the columns of the result that the next Execute returns.
+func (s *Server) result(sql string, consume bool) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.rules) - 1; i >= 0; i-- {
+		r := s.rules[i]
+		if !strings.Contains(sql, r.fragment) || len(r.results) == 0 {
+			continue
+		}
+		idx := r.used
+		if idx >= len(r.results) {
+			idx = len(r.results) - 1
+		}
+		if consume {
+			r.used++
+		}
+		return r.results[idx]
+	}
+	return Result{}
+}
+
+func (s *Server) record(stmt Statement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statements = append(s.statements, stmt)
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, c)
+				s.mu.Unlock()
+				c.Close()
+			}()
+			(&conn{server: s, backend: pgproto3.NewBackend(c, c), conn: c}).serve()
+		}()
+	}
+}
+
+type portal struct {
+	sql    string
+	params []interface{}
+}
+
+// conn is the state of one client connection.
+type conn struct {
+	server   *Server
+	backend  *pgproto3.Backend
+	conn     net.Conn
+	prepared map[string]string
+	portals  map[string]portal
+	txStatus byte
+	// failed is true after an error in the extended protocol, until the next Sync.
+	failed bool
+}
+
+func (c *conn) serve() {
+	if err := c.startup(); err != nil {
+		return
+	}
+	c.prepared, c.portals, c.txStatus = map[string]string{}, map[string]portal{}, 'I'
+	for {
+		msg, err := c.backend.Receive()
+		if err != nil {
+			return
+		}
+		if c.failed {
+			if _, ok := msg.(*pgproto3.Sync); !ok {
+				continue
+			}
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Query:
+			c.simpleQuery(msg.String)
+		case *pgproto3.Parse:
+			c.parse(msg)
+		case *pgproto3.Describe:
+			c.describe(msg)
+		case *pgproto3.Bind:
+			c.bind(msg)
+		case *pgproto3.Execute:
+			c.execute(msg)
+		case *pgproto3.Close:
+			if msg.ObjectType == 'S' {
+				delete(c.prepared, msg.Name)
+			} else {
+				delete(c.portals, msg.Name)
+			}
+			c.backend.Send(&pgproto3.CloseComplete{})
+		case *pgproto3.Sync:
+			c.failed = false
+			c.backend.Send(&pgproto3.ReadyForQuery{TxStatus: c.txStatus})
+		case *pgproto3.Flush:
+		case *pgproto3.Terminate:
+			return
+		default:
+			c.sendError(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "0A000", Message: fmt.Sprintf("unsupported message %T", msg)})
+		}
+		if err := c.backend.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *conn) startup() error {
+	for {
+		msg, err := c.backend.ReceiveStartupMessage()
+		if err != nil {
+			return err
+		}
+		switch msg.(type) {
+		case *pgproto3.SSLRequest, *pgproto3.GSSEncRequest:
+			if _, err := c.conn.Write([]byte("N")); err != nil {
+				return err
+			}
+		case *pgproto3.StartupMessage:
+			c.backend.Send(&pgproto3.AuthenticationOk{})
+			for name, value := range map[string]string{
+				"server_version":              "14.1",
+				"server_encoding":             "UTF8",
+				"client_encoding":             "UTF8",
+				"DateStyle":                   "ISO, MDY",
+				"TimeZone":                    "UTC",
+				"integer_datetimes":           "on",
+				"standard_conforming_strings": "on",
+			} {
+				c.backend.Send(&pgproto3.ParameterStatus{Name: name, Value: value})
+			}
+			c.backend.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+			c.backend.Send(&pgproto3.ReadyForQuery{TxStatus: 'I'})
+			return c.backend.Flush()
+		default:
+			return errors.New("unexpected startup message")
+		}
+	}
+}
+
+var paramPattern = regexp.MustCompile(`\$(\d+)`)
+
+// paramCount returns the highest parameter number in sql.
+func paramCount(sql string) int {
+	n := 0
+	for _, m := range paramPattern.FindAllStringSubmatch(sql, -1) {
+		if i, _ := strconv.Atoi(m[1]); i > n {
+			n = i
+		}
+	}
+	return n
+}
+
+func (c *conn) parse(msg *pgproto3.Parse) {
+	if max := c.server.MaxParams; max > 0 {
+		if n := paramCount(msg.Query); n > max {
+			c.sendError(&pgproto3.ErrorResponse{Severity: "ERROR", Code: TooManyParametersCode,
+				Message: fmt.Sprintf("statement has %d parameters, the maximum is %d", n, max)})
+			return
+		}
+	}
+	c.prepared[msg.Name] = msg.Query
+	c.backend.Send(&pgproto3.ParseComplete{})
+}
+
+func (c *conn) describe(msg *pgproto3.Describe) {
+	var sql string
+	if msg.ObjectType == 'S' {
+		query, ok := c.prepared[msg.Name]
+		if !ok {
+			c.sendError(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "26000", Message: "prepared statement does not exist: " + msg.Name})
+			return
+		}
+		c.backend.Send(&pgproto3.ParameterDescription{ParameterOIDs: make([]uint32, paramCount(query))})
+		sql = query
+	} else {
+		p, ok := c.portals[msg.Name]
+		if !ok {
+			c.sendError(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "34000", Message: "portal does not exist: " + msg.Name})
+			return
+		}
+		sql = p.sql
+	}
+	if res := c.server.result(sql, false); res.Err == nil && len(res.Columns) > 0 {
+		c.backend.Send(rowDescription(res.Columns))
+	} else {
+		c.backend.Send(&pgproto3.NoData{})
+	}
+}
+
+func (c *conn) bind(msg *pgproto3.Bind) {
+	query, ok := c.prepared[msg.PreparedStatement]
+	if !ok {
+		c.sendError(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "26000", Message: "prepared statement does not exist: " + msg.PreparedStatement})
+		return
+	}
+	params := make([]interface{}, len(msg.Parameters))
+	for i, p := range msg.Parameters {
+		switch {
+		case p == nil:
+		case formatCode(msg.ParameterFormatCodes, i) == 1:
+			params[i] = append([]byte(nil), p...)
+		default:
+			params[i] = string(p)
+		}
+	}
+	c.portals[msg.DestinationPortal] = portal{sql: query, params: params}
+	c.backend.Send(&pgproto3.BindComplete{})
+}
+
+// formatCode returns the format of parameter i. A single format code applies to all parameters.
+func formatCode(codes []int16, i int) int16 {
+	switch len(codes) {
+	case 0:
+		return 0
+	case 1:
+		return codes[0]
+	}
+	return codes[i]
+}
+
+func (c *conn) execute(msg *pgproto3.Execute) {
+	p, ok := c.portals[msg.Portal]
+	if !ok {
+		c.sendError(&pgproto3.ErrorResponse{Severity: "ERROR", Code: "34000", Message: "portal does not exist: " + msg.Portal})
+		return
+	}
+	c.run(p.sql, p.params, false)
+}
+
+func (c *conn) simpleQuery(sql string) {
+	if isEmptyQuery(sql) {
+		// pgx pings with a query that only contains a comment.
+		c.backend.Send(&pgproto3.EmptyQueryResponse{})
+	} else {
+		c.run(sql, nil, true)
+	}
+	c.failed = false
+	c.backend.Send(&pgproto3.ReadyForQuery{TxStatus: c.txStatus})
+}
+
+// run records sql and sends its result. The simple query protocol sends the row description with the rows.
+func (c *conn) run(sql string, params []interface{}, simple bool) {
+	c.server.record(Statement{SQL: sql, Params: params})
+	res := c.server.result(sql, true)
+	if res.Disconnect {
+		c.conn.Close()
+		return
+	}
+	if res.Err != nil {
+		c.sendError(res.Err)
+		return
+	}
+	if simple && len(res.Columns) > 0 {
+		c.backend.Send(rowDescription(res.Columns))
+	}
+	for _, row := range res.Rows {
+		values := make([][]byte, len(row))
+		for i, v := range row {
+			values[i] = encodeText(v)
+		}
+		c.backend.Send(&pgproto3.DataRow{Values: values})
+	}
+	tag := res.Tag
+	if tag == "" {
+		tag = defaultTag(sql, len(res.Rows))
+	}
+	c.backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(tag)})
+
+	switch strings.ToUpper(strings.Fields(tag + " ")[0]) {
+	case "BEGIN":
+		c.txStatus = 'T'
+	case "COMMIT", "ROLLBACK":
+		c.txStatus = 'I'
+	}
+}
+
+// isEmptyQuery returns true if sql only contains comments and whitespace.
+func isEmptyQuery(sql string) bool {
+	for _, line := range strings.Split(sql, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *conn) sendError(err *pgproto3.ErrorResponse) {
+	c.backend.Send(err)
+	c.failed = true
+	if c.txStatus == 'T' {
+		c.txStatus = 'E'
+	}
+}
+
+// defaultTag returns the command tag of a statement without a scripted tag. Statements that modify data report one
+// affected row.
+func defaultTag(sql string, rows int) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	switch keyword := strings.ToUpper(fields[0]); keyword {
+	case "INSERT":
+		if rows > 0 {
+			return fmt.Sprintf("INSERT 0 %d", rows)
+		}
+		return "INSERT 0 1"
+	case "UPDATE", "DELETE":
+		if rows > 0 {
+			return fmt.Sprintf("%s %d", keyword, rows)
+		}
+		return keyword + " 1"
+	case "SELECT", "WITH":
+		return fmt.Sprintf("SELECT %d", rows)
+	case "START":
+		return "BEGIN"
+	default:
+		return keyword
+	}
+}
+
+func rowDescription(columns []Column) *pgproto3.RowDescription {
+	fields := make([]pgproto3.FieldDescription, len(columns))
+	for i, col := range columns {
+		fields[i] = pgproto3.FieldDescription{Name: []byte(col.Name), DataTypeOID: col.OID, DataTypeSize: -1, TypeModifier: -1}
+	}
+	return &pgproto3.RowDescription{Fields: fields}
+}
+
+func encodeText(v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	case bool:
+		if v {
+			return []byte("t")
+		}
+		return []byte("f")
+	case time.Time:
+		return []byte(v.UTC().Format("2006-01-02 15:04:05.999999-07"))
+	}
+	return []byte(fmt.Sprint(v))
+}
@@ -0,0 +1,85 @@
+package pgfake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func connect(t *testing.T) (*Server, *pgconn.PgConn) {
+	t.Helper()
+	s, err := NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := pgconn.Connect(context.Background(), s.DSN())
+	if err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close(context.Background())
+		s.Close()
+	})
+	return s, conn
+}
+
+func TestExtendedProtocolRecordsParams(t *testing.T) {
+	s, conn := connect(t)
+	s.On("SELECT name", Rows([]Column{{Name: "name", OID: pgtype.TextOID}}, []interface{}{"a"}, []interface{}{nil}))
+
+	rr := conn.ExecParams(context.Background(), "SELECT name FROM t WHERE id = $1 AND x = $2",
+		[][]byte{[]byte("1"), nil}, nil, nil, nil)
+	var rows [][]byte
+	for rr.NextRow() {
+		rows = append(rows, rr.Values()[0])
+	}
+	tag, err := rr.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || string(rows[0]) != "a" || rows[1] != nil {
+		t.Errorf("got rows %q", rows)
+	}
+	if got := tag.String(); got != "SELECT 2" {
+		t.Errorf("got tag %q, want SELECT 2", got)
+	}
+	stmts := s.Statements()
+	if len(stmts) != 1 || stmts[0].Params[0] != "1" || stmts[0].Params[1] != nil {
+		t.Errorf("got statements %+v", stmts)
+	}
+}
+
+func TestScriptedResultsAreUsedInOrder(t *testing.T) {
+	s, conn := connect(t)
+	s.On("commit", Aborted(), Tag("COMMIT"))
+
+	for i, wantErr := range []bool{true, false, false} {
+		_, err := conn.Exec(context.Background(), "commit").ReadAll()
+		var pgErr *pgconn.PgError
+		if gotErr := errors.As(err, &pgErr) && pgErr.Code == "40001"; gotErr != wantErr {
+			t.Errorf("commit %d: got error %v, want aborted: %v", i, err, wantErr)
+		}
+	}
+	if got := len(s.Statements()); got != 3 {
+		t.Errorf("got %d statements, want 3", got)
+	}
+}
+
+func TestMaxParams(t *testing.T) {
+	s, conn := connect(t)
+	s.MaxParams = 1
+
+	_, err := conn.Prepare(context.Background(), "", "INSERT INTO t VALUES ($1, $2)", nil)
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != TooManyParametersCode {
+		t.Fatalf("got error %v, want %s", err, TooManyParametersCode)
+	}
+	// The connection can still be used after the error.
+	if err := conn.Exec(context.Background(), "SELECT 1").Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/datagen"
+)
+
+// loadTestRepository is the load test target that calls the repository in the process instead of the HTTP API.
+const loadTestRepository = "repository"
+
+// The operations of a load test.
+const (
+	opRegisterSingerWithAlbum = "register-singer-with-album"
+	opGetAlbumsOfSinger       = "get-albums-of-singer"
+	opListConcerts            = "list-concerts"
+)
+
+var loadTestOperations = []string{opRegisterSingerWithAlbum, opGetAlbumsOfSinger, opListConcerts}
+
+// LoadTestConfig configures the -loadtest mode.
+type LoadTestConfig struct {
+	// Target is either repository, or the base URL of the HTTP API, like http://localhost:8080.
+	Target string `yaml:"target" env:"LOADTEST_TARGET" flag:"loadtest-target" usage:"repository, or the base URL of the HTTP API to load test"`
+	// APIKey is sent in the X-API-Key header to an HTTP API that requires authentication.
+	APIKey string `yaml:"api_key" env:"LOADTEST_API_KEY" flag:"loadtest-api-key" secret:"true" usage:"API key for the HTTP API"`
+	// Mix contains the relative weights of the operations, like register-singer-with-album=1.
+	Mix         []string      `yaml:"mix" env:"LOADTEST_MIX" flag:"loadtest-mix" usage:"Comma separated operation=weight pairs of register-singer-with-album, get-albums-of-singer and list-concerts"`
+	Concurrency int           `yaml:"concurrency" env:"LOADTEST_CONCURRENCY" flag:"loadtest-concurrency" usage:"Number of concurrent load test workers"`
+	Rate        float64       `yaml:"rate" env:"LOADTEST_RATE" flag:"loadtest-rate" usage:"Total operations per second of the load test, 0 for unlimited"`
+	Duration    time.Duration `yaml:"duration" env:"LOADTEST_DURATION" flag:"loadtest-duration" usage:"Duration of the load test"`
+	// Format is the format of the report, table or json.
+	Format string `yaml:"format" env:"LOADTEST_FORMAT" flag:"loadtest-format" usage:"Format of the load test report: table or json"`
+}
+
+// weights returns the weight of each operation in the mix.
+func (c LoadTestConfig) weights() (map[string]int, error) {
+	weights := map[string]int{}
+	for _, item := range c.Mix {
+		name, value, _ := strings.Cut(item, "=")
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight in %q, use operation=weight with a non-negative weight", item)
+		}
+		known := false
+		for _, op := range loadTestOperations {
+			known = known || op == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown operation %q, use one of %s", name, strings.Join(loadTestOperations, ", "))
+		}
+		weights[name] = weight
+	}
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("the weight of at least one operation must be positive")
+	}
+	return weights, nil
+}
+
+// validate returns the problems of the load test configuration.
+func (c LoadTestConfig) validate() []string {
+	var errs []string
+	if c.Target != loadTestRepository {
+		if u, err := url.Parse(c.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("loadtest.target must be %s or an http(s) URL, got %q", loadTestRepository, c.Target))
+		}
+	}
+	if _, err := c.weights(); err != nil {
+		errs = append(errs, "loadtest.mix: "+err.Error())
+	}
+	if c.Concurrency < 1 {
+		errs = append(errs, "loadtest.concurrency must be at least 1")
+	}
+	if c.Rate < 0 {
+		errs = append(errs, "loadtest.rate must not be negative")
+	}
+	if c.Duration <= 0 {
+		errs = append(errs, "loadtest.duration must be positive")
+	}
+	if c.Format != "table" && c.Format != "json" {
+		errs = append(errs, fmt.Sprintf("loadtest.format must be table or json, got %q", c.Format))
+	}
+	return errs
+}
+
+// loadTestClient executes the operations of a load test.
+type loadTestClient interface {
+	registerSingerWithAlbum(ctx context.Context, info SingerAlbumInfo) (singerId string, err error)
+	getAlbumsOfSinger(ctx context.Context, singerId string) error
+	listConcerts(ctx context.Context) error
+	// transactionRetries returns the number of transactions that have been retried by the server so far.
+	transactionRetries(ctx context.Context) (float64, error)
+}
+
+// repoLoadTestClient calls the repository of the default tenant in the process.
+type repoLoadTestClient struct {
+	m MusicDbOperation
+}
+
+func (c repoLoadTestClient) registerSingerWithAlbum(ctx context.Context, info SingerAlbumInfo) (string, error) {
+	singerId, _, err := c.m.registerSingerWithAlbum(ctx, info.FirstName, info.LastName, info.AlbumName)
+	return singerId, err
+}
+
+func (c repoLoadTestClient) getAlbumsOfSinger(ctx context.Context, singerId string) error {
+	_, err := c.m.repo.AlbumsOfSinger(ctx, singerId)
+	return err
+}
+
+func (c repoLoadTestClient) listConcerts(ctx context.Context) error {
+	_, err := c.m.repo.FindConcerts(ctx, ConcertFilter{Limit: defaultConcertLimit}, true)
+	return err
+}
+
+func (c repoLoadTestClient) transactionRetries(context.Context) (float64, error) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return 0, err
+	}
+	for _, family := range families {
+		if family.GetName() == "db_transaction_retries_total" && len(family.GetMetric()) > 0 {
+			return family.GetMetric()[0].GetCounter().GetValue(), nil
+		}
+	}
+	return 0, nil
+}
+
+// httpLoadTestClient calls the HTTP API at baseURL.
+type httpLoadTestClient struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+}
+
+// httpStatusError is returned by the httpLoadTestClient for responses with an error status.
+type httpStatusError struct {
+	status  int
+	message string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.status, e.message)
+}
+
+func (c httpLoadTestClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errResp ErrorResponse
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &errResp) != nil || errResp.Error == "" {
+			errResp.Error = strings.TrimSpace(string(data))
+		}
+		return &httpStatusError{status: resp.StatusCode, message: errResp.Error}
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c httpLoadTestClient) registerSingerWithAlbum(ctx context.Context, info SingerAlbumInfo) (string, error) {
+	var ids SingerAlbumIds
+	err := c.do(ctx, http.MethodPost, "/api/register-singer-with-album", info, &ids)
+	return ids.SingerId, err
+}
+
+func (c httpLoadTestClient) getAlbumsOfSinger(ctx context.Context, singerId string) error {
+	return c.do(ctx, http.MethodGet, "/api/get-albums-of-singerid/"+url.PathEscape(singerId), nil, nil)
+}
+
+func (c httpLoadTestClient) listConcerts(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/api/concerts", nil, nil)
+}
+
+var transactionRetriesMetric = regexp.MustCompile(`(?m)^db_transaction_retries_total (\S+)$`)
+
+// transactionRetries reads the retries from the /metrics endpoint of the server.
+func (c httpLoadTestClient) transactionRetries(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.baseURL, "/")+"/metrics", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	match := transactionRetriesMetric.FindSubmatch(data)
+	if match == nil {
+		return 0, errors.New("db_transaction_retries_total not found in /metrics")
+	}
+	return strconv.ParseFloat(string(match[1]), 64)
+}
+
+// loadTestReport is the result of a load test.
+type loadTestReport struct {
+	Target      string  `json:"target"`
+	Concurrency int     `json:"concurrency"`
+	Rate        float64 `json:"rate"`
+	Duration    float64 `json:"duration_seconds"`
+	// Operations contains the results per operation, followed by the total.
+	Operations []loadTestOperationReport `json:"operations"`
+	// Errors counts the errors by type, such as an HTTP status or a SQLSTATE.
+	Errors map[string]int `json:"errors"`
+	// TransactionRetries is the number of transactions that were retried, and Aborts the number of operations that
+	// failed because their transaction was aborted too often. Retries are unknown if /metrics could not be read.
+	TransactionRetries *float64 `json:"transaction_retries"`
+	Aborts             int      `json:"aborts"`
+}
+
+type loadTestOperationReport struct {
+	Name       string  `json:"name"`
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	P50        float64 `json:"p50_ms"`
+	P95        float64 `json:"p95_ms"`
+	P99        float64 `json:"p99_ms"`
+}
+
+// loadTestResults collects the latencies and errors of the operations of the workers.
+type loadTestResults struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	opErrors  map[string]int
+	aborts    int
+	singerIds []string
+}
+
+func (r *loadTestResults) add(op string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies[op] = append(r.latencies[op], latency)
+	if err != nil {
+		errType := loadTestErrorType(err)
+		r.errors[errType]++
+		r.opErrors[op]++
+		if strings.Contains(errType, "SQLSTATE 40001") {
+			r.aborts++
+		}
+	}
+}
+
+func (r *loadTestResults) addSinger(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.singerIds = append(r.singerIds, id)
+}
+
+// randomSinger returns a singer that was registered by the load test, or false if there is none yet.
+func (r *loadTestResults) randomSinger(gen *datagen.Generator) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.singerIds) == 0 {
+		return "", false
+	}
+	return r.singerIds[gen.Intn(len(r.singerIds))], true
+}
+
+var sqlStateInMessage = regexp.MustCompile(`SQLSTATE \w{5}`)
+
+// loadTestErrorType returns the type of err for the error breakdown of the report.
+func loadTestErrorType(err error) string {
+	var statusErr *httpStatusError
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &statusErr):
+		// The server returns database errors with status 500 and the error message of pgconn.
+		if code := sqlStateInMessage.FindString(statusErr.message); code != "" {
+			return fmt.Sprintf("HTTP %d %s", statusErr.status, code)
+		}
+		return fmt.Sprintf("HTTP %d", statusErr.status)
+	case errors.As(err, &pgErr):
+		return "SQLSTATE " + pgErr.Code
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case isConnectionError(err):
+		return "connection"
+	default:
+		return "other"
+	}
+}
+
+// loadTest runs the load test of cfg with client until it has finished or the process is interrupted, and prints the
+// report.
+func loadTest(cfg *Config, client loadTestClient) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	report, err := runLoadTest(ctx, cfg.LoadTest, client)
+	if err != nil {
+		return err
+	}
+	return report.write(os.Stdout, cfg.LoadTest.Format)
+}
+
+// runLoadTest executes the operations of cfg with client until the duration has passed or ctx is done, and returns
+// the report. Operations that are in flight at the end are completed and included in the report.
+func runLoadTest(ctx context.Context, cfg LoadTestConfig, client loadTestClient) (*loadTestReport, error) {
+	weights, err := cfg.weights()
+	if err != nil {
+		return nil, err
+	}
+	var ops []string
+	for _, op := range loadTestOperations {
+		for i := 0; i < weights[op]; i++ {
+			ops = append(ops, op)
+		}
+	}
+	limit := rate.Inf
+	if cfg.Rate > 0 {
+		limit = rate.Limit(cfg.Rate)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+	retriesBefore, retriesErr := client.transactionRetries(ctx)
+
+	results := &loadTestResults{latencies: map[string][]time.Duration{}, errors: map[string]int{}, opErrors: map[string]int{}}
+	runCtx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gen := newDataGenerator()
+			for limiter.Wait(runCtx) == nil {
+				op := ops[gen.Intn(len(ops))]
+				singerId, ok := results.randomSinger(gen)
+				if op == opGetAlbumsOfSinger && !ok {
+					// Albums can only be looked up for singers that were registered by the load test.
+					op = opRegisterSingerWithAlbum
+				}
+				begin := time.Now()
+				var err error
+				switch op {
+				case opRegisterSingerWithAlbum:
+					info := SingerAlbumInfo{FirstName: gen.FirstName(), LastName: gen.LastName(), AlbumName: gen.AlbumTitle()}
+					if singerId, err = client.registerSingerWithAlbum(ctx, info); err == nil {
+						results.addSinger(singerId)
+					}
+				case opGetAlbumsOfSinger:
+					err = client.getAlbumsOfSinger(ctx, singerId)
+				case opListConcerts:
+					err = client.listConcerts(ctx)
+				}
+				results.add(op, time.Since(begin), err)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report := &loadTestReport{
+		Target:      cfg.Target,
+		Concurrency: cfg.Concurrency,
+		Rate:        cfg.Rate,
+		Duration:    elapsed.Seconds(),
+		Errors:      results.errors,
+		Aborts:      results.aborts,
+	}
+	var all []time.Duration
+	totalErrors := 0
+	for _, op := range loadTestOperations {
+		latencies := results.latencies[op]
+		if len(latencies) == 0 {
+			continue
+		}
+		all = append(all, latencies...)
+		totalErrors += results.opErrors[op]
+		report.Operations = append(report.Operations, operationReport(op, latencies, results.opErrors[op], elapsed))
+	}
+	report.Operations = append(report.Operations, operationReport("total", all, totalErrors, elapsed))
+	if retriesErr == nil {
+		if retriesAfter, err := client.transactionRetries(ctx); err == nil {
+			retries := retriesAfter - retriesBefore
+			report.TransactionRetries = &retries
+		}
+	}
+	return report, nil
+}
+
+func operationReport(name string, latencies []time.Duration, errors int, elapsed time.Duration) loadTestOperationReport {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return loadTestOperationReport{
+		Name:       name,
+		Requests:   len(latencies),
+		Errors:     errors,
+		Throughput: float64(len(latencies)) / elapsed.Seconds(),
+		P50:        percentile(latencies, 0.50),
+		P95:        percentile(latencies, 0.95),
+		P99:        percentile(latencies, 0.99),
+	}
+}
+
+// percentile returns the q-th percentile of the sorted latencies in milliseconds.
+func percentile(sorted []time.Duration, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return float64(sorted[i].Microseconds()) / 1000
+}
+
+// write writes the report as a table or as JSON.
+func (r *loadTestReport) write(w io.Writer, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "target\t%s\n", r.Target)
+	fmt.Fprintf(tw, "concurrency\t%d\n", r.Concurrency)
+	if r.Rate > 0 {
+		fmt.Fprintf(tw, "rate\t%g/s\n", r.Rate)
+	} else {
+		fmt.Fprintf(tw, "rate\tunlimited\n")
+	}
+	fmt.Fprintf(tw, "duration\t%.1fs\n", r.Duration)
+	if r.TransactionRetries != nil {
+		fmt.Fprintf(tw, "transaction retries\t%g\n", *r.TransactionRetries)
+	} else {
+		fmt.Fprintf(tw, "transaction retries\tunknown\n")
+	}
+	fmt.Fprintf(tw, "aborts\t%d\n", r.Aborts)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "operation\trequests\terrors\treq/s\tp50 ms\tp95 ms\tp99 ms")
+	for _, op := range r.Operations {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.1f\t%.1f\t%.1f\n", op.Name, op.Requests, op.Errors, op.Throughput, op.P50, op.P95, op.P99)
+	}
+	if len(r.Errors) > 0 {
+		types := make([]string, 0, len(r.Errors))
+		for errType := range r.Errors {
+			types = append(types, errType)
+		}
+		sort.Strings(types)
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "error\tcount")
+		for _, errType := range types {
+			fmt.Fprintf(tw, "%s\t%d\n", errType, r.Errors[errType])
+		}
+	}
+	return tw.Flush()
+}
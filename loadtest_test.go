@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunLoadTest(t *testing.T) {
+	cfg := defaultConfig().LoadTest
+	cfg.Concurrency = 4
+	cfg.Rate = 0
+	cfg.Duration = 200 * time.Millisecond
+	m := MusicDbOperation{repo: newMemoryRepository()}
+
+	report, err := runLoadTest(context.Background(), cfg, repoLoadTestClient{m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := report.Operations[len(report.Operations)-1]
+	if total.Name != "total" || total.Requests == 0 || total.Errors != 0 {
+		t.Fatalf("got total %+v, want requests without errors", total)
+	}
+	requests := 0
+	for _, op := range report.Operations[:len(report.Operations)-1] {
+		requests += op.Requests
+		if op.P50 > op.P95 || op.P95 > op.P99 {
+			t.Errorf("%s: percentiles are not ordered: %+v", op.Name, op)
+		}
+	}
+	if requests != total.Requests {
+		t.Errorf("got %d requests in the operations, want %d", requests, total.Requests)
+	}
+	if report.TransactionRetries == nil {
+		t.Error("transaction retries are unknown")
+	}
+
+	var out bytes.Buffer
+	if err := report.write(&out, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded loadTestReport
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := report.write(&out, "table"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), opRegisterSingerWithAlbum) {
+		t.Errorf("table does not contain %s:\n%s", opRegisterSingerWithAlbum, out.String())
+	}
+}
+
+func TestLoadTestConfigValidate(t *testing.T) {
+	cfg := defaultConfig().LoadTest
+	if errs := cfg.validate(); len(errs) != 0 {
+		t.Fatalf("default configuration is invalid: %v", errs)
+	}
+	cfg.Target = "localhost:8080"
+	cfg.Mix = []string{"list-singers=1"}
+	cfg.Format = "csv"
+	if errs := cfg.validate(); len(errs) != 3 {
+		t.Errorf("got errors %v, want 3", errs)
+	}
+}
@@ -9,99 +9,292 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog"
 	"github.com/go-chi/render"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
 var appName = "sampleapp"
 
-// like, CONNECTION_STRING="host=localhost port=5432"
-var connString string = os.Getenv("CONNECTION_STRING")
-var servicePort string = os.Getenv("PORT")
-var logLevel logger.LogLevel = logger.Info // to show SQL generated by GORM
-
-var maxRetry = 10
-
 type MusicDbOperation struct {
-	db *gorm.DB
+	db      *gorm.DB
+	repo    MusicRepository
+	cfg     *Config
+	state   *serverState
+	tenants *tenantRouter
 }
 
-func newDbConn(connString string, logMode logger.LogLevel) (*gorm.DB, error) {
-	log.Println("connString ", connString)
-	for i := 0; i < maxRetry; i++ {
-		db, err := gorm.Open(postgres.Open(connString), &gorm.Config{
+// newDbConn connects to PGAdapter. PGAdapter is started next to the application by supervisord and might not
+// accept connections yet, so the connection is retried with exponential backoff until cfg.MaxRetry attempts or
+// cfg.ConnectTimeout have passed.
+func newDbConn(cfg *Config, gormLogger logger.Interface) (*gorm.DB, error) {
+	log.Println("connString ", redactedDSN(cfg.DSN()))
+	deadline := time.Now().Add(cfg.ConnectTimeout)
+	var err error
+	for attempt := 1; attempt <= cfg.MaxRetry; attempt++ {
+		var db *gorm.DB
+		db, err = gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
 			DisableNestedTransaction: true,
-			Logger:                   logger.Default.LogMode(logMode),
+			Logger:                   gormLogger,
 		})
-		if err != nil {
-			log.Println(" Retrying...", i+1, " ", err)
-			time.Sleep(time.Second * 2)
-			continue
+		if err == nil {
+			return db, configureConnPool(db, cfg)
 		}
-		return db, nil
+		wait := backoff(attempt, time.Second/2, 10*time.Second)
+		if attempt == cfg.MaxRetry || time.Now().Add(wait).After(deadline) {
+			break
+		}
+		log.Println(" Retrying...", attempt, " ", err, " in ", wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+	return nil, fmt.Errorf("connection failure: %w", err)
+}
+
+// configureConnPool applies the pool settings of cfg and installs the retryingConnPool and the spannerGuard.
+func configureConnPool(db *gorm.DB, cfg *Config) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	pool := &retryingConnPool{db: sqlDB}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return db.Use(spannerGuard{strict: cfg.SpannerGuardStrict})
+}
+
+// instrumentDb installs the metrics, tracing and fault injection plugins on the database of a tenant and exports its pool statistics.
+func instrumentDb(db *gorm.DB, tenant string) error {
+	if err := db.Use(gormMetrics{tenant: tenant}); err != nil {
+		return err
 	}
-	return nil, errors.New("connection failure")
+	if err := db.Use(gormTracing{}); err != nil {
+		return err
+	}
+	if err := db.Use(gormFaults{}); err != nil {
+		return err
+	}
+	return registerDbStatsCollector(db, tenant)
+}
+
+// closeDbConn closes the connection pool of db. Connections that are in use are closed when they are returned to
+// the pool, so this should only be called after all requests have finished.
+func closeDbConn(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
 func main() {
 
-	init := flag.Bool("init", false, "Generate initial data")
-	flag.Parse()
+	if len(os.Args) > 1 && os.Args[1] == "sample" {
+		err := sampleCommand(os.Args[2:], os.Stdout)
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		err := replayCommand(os.Args[2:], os.Stdout)
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	db, err := newDbConn(connString, logLevel)
+	if err := run(os.Args[1:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+		log.Fatal(err)
+	}
+}
 
+// run starts the server, or runs the data initialization or the load test, with the configuration of args. Errors
+// are returned instead of exiting, so that the deferred flush of the traces and close of the recorder always run.
+func run(args []string) error {
+	cfg, err := LoadConfig(args)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if cfg.PrintConfig {
+		return cfg.WriteRedacted(os.Stdout)
+	}
+	if err := configureDataGen(cfg); err != nil {
+		return err
 	}
 
-	defer func() {
-		sqlDB, _ := db.DB()
-		sqlDB.Close()
-	}()
+	if cfg.RunLoadTest && cfg.LoadTest.Target != loadTestRepository {
+		client := httpLoadTestClient{client: &http.Client{Timeout: cfg.RequestTimeout}, baseURL: cfg.LoadTest.Target, apiKey: cfg.LoadTest.APIKey}
+		return loadTest(cfg, client)
+	}
+
+	faultInjection.set(cfg.FaultInjection)
 
-	m := MusicDbOperation{db: db}
+	/* jsonify logging */
+	httpLogger := httplog.NewLogger(appName, httplog.Options{JSON: true, LevelFieldName: "severity", Concise: true})
 
-	if *init {
+	db, err := newDbConn(cfg, newJsonGormLogger(httpLogger, cfg))
+	if err != nil {
+		return err
+	}
+	// serve closes the pool after the drain period already. Closing it again does nothing.
+	defer closeDbConn(db)
+
+	if err := instrumentDb(db, defaultTenant); err != nil {
+		return err
+	}
+
+	m := MusicDbOperation{db: db, repo: newGormRepository(db), cfg: cfg, state: &serverState{}, tenants: newTenantRouter(cfg, httpLogger)}
+
+	if cfg.InitData {
 		m.db.Logger = m.db.Logger.LogMode(logger.Error)
 		m.initData()
-		return
+		return nil
 	}
 
-	ctx := context.Background()
+	if cfg.RunLoadTest {
+		m.db.Logger = m.db.Logger.LogMode(logger.Error)
+		return loadTest(cfg, repoLoadTestClient{m})
+	}
 
-	oplog := httplog.LogEntry(ctx)
-	/* jsonify logging */
-	httpLogger := httplog.NewLogger(appName, httplog.Options{JSON: true, LevelFieldName: "severity", Concise: true})
+	// Cloud Run sends SIGTERM before an instance is shut down.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
 
+	shutdownTracing, err := initTracing(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Println("failed to flush spans:", err)
+		}
+	}()
+
+	authn, err := newAuth(cfg)
+	if err != nil {
+		return err
+	}
+	var recorder *requestRecorder
+	if cfg.RecordRequests != "" {
+		if recorder, err = newRequestRecorder(cfg.RecordRequests); err != nil {
+			return err
+		}
+		defer recorder.Close()
+	}
+	// The HTTP and gRPC servers share the limiter, so that clients have the same limits on both.
+	limiter := newRateLimiter(cfg)
+	r, err := m.newRouter(authn, limiter, httpLogger, recorder)
+	if err != nil {
+		return err
+	}
+
+	if err := serve(ctx, cfg, r, newGrpcServer(m, authn, limiter), m, httpLogger); err != nil {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+// newRouter returns the router of the HTTP server with all middlewares and routes. If recorder is set, the API
+// requests are recorded.
+func (m MusicDbOperation) newRouter(authn *auth, limiter *rateLimiter, httpLogger zerolog.Logger, recorder *requestRecorder) (chi.Router, error) {
+	cfg := m.cfg
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
+	r.Use(tracingMiddleware)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(middleware.Timeout(cfg.RequestTimeout))
 	r.Use(httplog.RequestLogger(httpLogger))
+	r.Use(m.tenants.middleware)
+	r.Use(metricsMiddleware)
+	r.Use(authn.middleware)
+
+	openAPIDoc, err := newOpenAPIDocument(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.OpenAPIValidation != "off" {
+		validator, err := openAPIValidator(openAPIDoc, cfg.OpenAPIValidation == "response")
+		if err != nil {
+			return nil, err
+		}
+		r.Use(validator)
+	}
+	r.Get("/openapi.json", openAPIHandler(openAPIDoc))
 
 	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
 		render.JSON(w, r, map[string]string{"message": "pong"})
 	})
+	r.Get("/healthz", m.healthz)
+	r.Handle("/metrics", promhttp.Handler())
+	r.Get("/readyz", m.readyz)
+
+	gqlSchema, err := m.newGraphqlSchema()
+	if err != nil {
+		return nil, err
+	}
+	// Only the routes that access the database are rate limited, so that probes and metrics are never rejected.
+	idempotency := newIdempotencyStore(cfg)
+	r.Group(func(r chi.Router) {
+		r.Use(requireReaderOrEditor)
+		r.Use(m.tenants.selectTenant)
+		r.Use(limiter.middleware)
+		r.Use(faultInjection.middleware)
+		if recorder != nil {
+			// Requests that are rejected by the rate limiter or fail because of an injected fault are not recorded.
+			r.Use(recorder.middleware)
+		}
+		r.Get("/graphql", m.graphqlHandler(gqlSchema))
+		r.Post("/graphql", m.graphqlHandler(gqlSchema))
 
-	r.Route("/api", func(s chi.Router) {
-		s.Get("/get-albums-of-singerid/{singerId}", m.getAlbumInfoWithSingerId)
-		s.Post("/register-singer-with-album", m.createSingerAlbum)
+		r.Route("/api", func(s chi.Router) {
+			s.Use(idempotency.middleware)
+			s.Get("/get-albums-of-singerid/{singerId}", m.getAlbumInfoWithSingerId)
+			s.Post("/register-singer-with-album", m.createSingerAlbum)
+			s.Get("/concerts", m.listConcerts)
+			s.Get("/venues/{venueId}/concerts.ics", m.exportVenueCalendar)
+			s.Get("/singers/{singerId}/concerts.ics", m.exportSingerCalendar)
+			s.Route("/stats", func(st chi.Router) {
+				st.Get("/singers", m.getSingerStats)
+				st.Get("/sample-rates", m.getSampleRateStats)
+				st.Get("/albums-per-decade", m.getAlbumsPerDecade)
+			})
+		})
 	})
 
-	if servicePort != "" {
-		servicePort = "8080"
+	// The admin routes are only mounted with authentication, as they can change the behavior of the instance.
+	if authn.enabled {
+		r.Route("/admin", func(a chi.Router) {
+			a.Use(requireRole(roleAdmin))
+			a.Get("/config", m.adminConfig)
+			a.Get("/faults", m.adminGetFaults)
+			a.Put("/faults", m.adminPutFaults)
+			a.Delete("/faults", m.adminDeleteFaults)
+		})
 	}
+	return r, nil
+}
 
-	if err := http.ListenAndServe(":"+servicePort, r); err != nil {
-		oplog.Err(err)
+// adminConfig returns the effective configuration with secrets redacted.
+func (m MusicDbOperation) adminConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	if err := m.cfg.WriteRedacted(w); err != nil {
+		oplog := httplog.LogEntry(r.Context())
+		oplog.Error().Err(err).Msg("failed to write the configuration")
 	}
 }
 
@@ -111,13 +304,6 @@ var errorRender = func(w http.ResponseWriter, r *http.Request, httpCode int, err
 }
 
 func (m MusicDbOperation) createSingerAlbum(w http.ResponseWriter, r *http.Request) {
-
-	type SingerAlbumInfo struct {
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
-		AlbumName string `json:"album_name"`
-	}
-
 	postData := SingerAlbumInfo{}
 
 	decoder := json.NewDecoder(r.Body)
@@ -128,34 +314,36 @@ func (m MusicDbOperation) createSingerAlbum(w http.ResponseWriter, r *http.Reque
 	}
 	defer r.Body.Close()
 
-	var (
-		newSingerId string
-		newAlbumId  string
-	)
-	if err := m.db.Transaction(func(tx *gorm.DB) error {
-		singerId, err := CreateSinger(tx, postData.FirstName, postData.LastName)
-		if err != nil {
+	newSingerId, newAlbumId, err := m.registerSingerWithAlbum(r.Context(), postData.FirstName, postData.LastName, postData.AlbumName)
+	if err != nil {
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	render.JSON(w, r, SingerAlbumIds{SingerId: newSingerId, AlbumId: newAlbumId})
+}
+
+// registerSingerWithAlbum creates a singer and an album with a random number of tracks in one transaction.
+// It is shared by the HTTP and the gRPC API.
+func (m MusicDbOperation) registerSingerWithAlbum(ctx context.Context, firstName, lastName, albumName string) (singerId, albumId string, err error) {
+	err = m.repoFor(ctx).Transaction(ctx, func(tx MusicRepository) error {
+		singer := newSinger(firstName, lastName)
+		if err := tx.CreateSinger(ctx, singer); err != nil {
 			return err
 		}
-		albumId, err := CreateAlbumWithRandomTracks(tx, singerId, postData.AlbumName, randInt(1, 22))
-		if err != nil {
+		gen := newDataGenerator()
+		album, tracks := newAlbumWithRandomTracks(gen, singer.ID, albumName, gen.TrackCount())
+		if err := tx.CreateAlbum(ctx, album); err != nil {
 			return err
 		}
-		newSingerId = singerId
-		newAlbumId = albumId
-		return nil
-	}); err != nil {
-		errorRender(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	render.JSON(w, r, map[string]string{"singer_id": newSingerId, "album_id": newAlbumId})
+		singerId, albumId = singer.ID, album.ID
+		return tx.CreateTracks(ctx, tracks)
+	})
+	return singerId, albumId, err
 }
 
 func (m MusicDbOperation) getAlbumInfoWithSingerId(w http.ResponseWriter, r *http.Request) {
-	var albums []*Album
-	singerId := chi.URLParam(r, "singerId")
-	if err := m.db.Model(&Album{}).Preload(clause.Associations).
-		Where("singer_id = ?", singerId).Find(&albums).Error; err != nil {
+	albums, err := m.repoFor(r.Context()).AlbumsOfSinger(r.Context(), chi.URLParam(r, "singerId"))
+	if err != nil {
 		errorRender(w, r, http.StatusInternalServerError, err)
 		return
 	}
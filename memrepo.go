@@ -0,0 +1,829 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// SQLSTATE codes of the constraint violations that memoryRepository returns.
+const (
+	sqlStateUndefinedColumn     = "42703"
+	sqlStateForeignKeyViolation = "23503"
+	sqlStateUniqueViolation     = "23505"
+	sqlStateCheckViolation      = "23514"
+)
+
+// memoryRepository implements MusicRepository in memory, so that handlers can be tested without a database. It
+// enforces the same constraints as the data model in schemas/create_data_model.sql: the foreign keys of albums and
+// concerts, the interleaving of tracks in albums with ON DELETE CASCADE and the check that concerts end after they
+// start.
+//
+// Each operation is atomic. Transactions are serialized, and operations on the repository itself block until the
+// running transaction has finished, so fn of Transaction must only use the repository that it is given.
+type memoryRepository struct {
+	mu   sync.Mutex
+	data *memoryData
+	// inTransaction is true for the repository that is passed to the function of Transaction.
+	inTransaction bool
+}
+
+type trackKey struct {
+	albumId     string
+	trackNumber int64
+}
+
+// memoryData holds the records of a memoryRepository without their associations. Records are never modified after
+// they have been stored, so that a copy of the maps is a snapshot of the data.
+type memoryData struct {
+	singers  map[string]*Singer
+	albums   map[string]*Album
+	tracks   map[trackKey]*Track
+	venues   map[string]*Venue
+	concerts map[string]*Concert
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{data: &memoryData{
+		singers:  map[string]*Singer{},
+		albums:   map[string]*Album{},
+		tracks:   map[trackKey]*Track{},
+		venues:   map[string]*Venue{},
+		concerts: map[string]*Concert{},
+	}}
+}
+
+func (d *memoryData) clone() *memoryData {
+	c := &memoryData{
+		singers:  make(map[string]*Singer, len(d.singers)),
+		albums:   make(map[string]*Album, len(d.albums)),
+		tracks:   make(map[trackKey]*Track, len(d.tracks)),
+		venues:   make(map[string]*Venue, len(d.venues)),
+		concerts: make(map[string]*Concert, len(d.concerts)),
+	}
+	for k, v := range d.singers {
+		c.singers[k] = v
+	}
+	for k, v := range d.albums {
+		c.albums[k] = v
+	}
+	for k, v := range d.tracks {
+		c.tracks[k] = v
+	}
+	for k, v := range d.venues {
+		c.venues[k] = v
+	}
+	for k, v := range d.concerts {
+		c.concerts[k] = v
+	}
+	return c
+}
+
+// read executes fn on the data of the repository.
+func (r *memoryRepository) read(ctx context.Context, fn func(d *memoryData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fn(r.data)
+}
+
+// write executes fn on a copy of the data of the repository, which replaces the data if fn succeeds.
+func (r *memoryRepository) write(ctx context.Context, fn func(d *memoryData) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.data.clone()
+	if err := fn(d); err != nil {
+		return err
+	}
+	r.data = d
+	return nil
+}
+
+func (r *memoryRepository) Transaction(ctx context.Context, fn func(tx MusicRepository) error) error {
+	if r.inTransaction {
+		return fn(r)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx := &memoryRepository{data: r.data.clone(), inTransaction: true}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.data = tx.data
+	return nil
+}
+
+func constraintError(code, constraint, format string, args ...interface{}) error {
+	return &pgconn.PgError{Severity: "ERROR", Code: code, ConstraintName: constraint, Message: fmt.Sprintf(format, args...)}
+}
+
+func duplicateKeyError(table, id string) error {
+	return constraintError(sqlStateUniqueViolation, table+"_pkey", "duplicate key value violates unique constraint on %s: %s", table, id)
+}
+
+// missingReferenceError is returned if a record references a record that does not exist.
+func missingReferenceError(constraint, table, id string) error {
+	return constraintError(sqlStateForeignKeyViolation, constraint, "insert or update violates foreign key constraint %s: %s %s does not exist", constraint, table, id)
+}
+
+// stillReferencedError is returned if a record that is deleted is still referenced by other records.
+func stillReferencedError(constraint, table, id string) error {
+	return constraintError(sqlStateForeignKeyViolation, constraint, "delete violates foreign key constraint %s: %s %s is still referenced", constraint, table, id)
+}
+
+// setCreateTimes sets the timestamps of a new record like GORM does.
+func setCreateTimes(m *BaseModel, now time.Time) {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = now
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = now
+	}
+}
+
+// fullName computes the generated full_name column of singers.
+func fullName(s *Singer) string {
+	if s.FirstName.Valid {
+		return s.FirstName.String + " " + s.LastName
+	}
+	return s.LastName
+}
+
+var memorySchemas sync.Map
+
+// setColumns applies updates given as a map from column name to value to record.
+func setColumns(record interface{}, updates map[string]interface{}) error {
+	s, err := schema.Parse(record, &memorySchemas, schema.NamingStrategy{})
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(record)
+	for column, value := range updates {
+		field := s.LookUpField(column)
+		if field == nil || field.DBName == "" {
+			return constraintError(sqlStateUndefinedColumn, "", "column %q of relation %q does not exist", column, s.Table)
+		}
+		if err := field.Set(context.Background(), rv, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func paginateSlice[T any](records []T, page Page) []T {
+	if page.Offset > 0 {
+		if page.Offset >= len(records) {
+			return records[:0]
+		}
+		records = records[page.Offset:]
+	}
+	if page.Limit > 0 && page.Limit < len(records) {
+		records = records[:page.Limit]
+	}
+	return records
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func copySinger(s *Singer) *Singer {
+	c := *s
+	c.Albums = nil
+	return &c
+}
+
+func copyAlbum(a *Album) *Album {
+	c := *a
+	c.Singer, c.Tracks = Singer{}, nil
+	return &c
+}
+
+func copyTrack(t *Track) *Track {
+	c := *t
+	c.Album = Album{}
+	return &c
+}
+
+func copyVenue(v *Venue) *Venue {
+	c := *v
+	return &c
+}
+
+func copyConcert(c *Concert) *Concert {
+	cc := *c
+	cc.Venue, cc.Singer = Venue{}, Singer{}
+	return &cc
+}
+
+func (d *memoryData) checkAlbum(a *Album) error {
+	if _, ok := d.singers[a.SingerId]; !ok {
+		return missingReferenceError("fk_albums_singers", "singer", a.SingerId)
+	}
+	return nil
+}
+
+func (d *memoryData) checkConcert(c *Concert) error {
+	if _, ok := d.venues[c.VenueId]; !ok {
+		return missingReferenceError("fk_concerts_venues", "venue", c.VenueId)
+	}
+	if _, ok := d.singers[c.SingerId]; !ok {
+		return missingReferenceError("fk_concerts_singers", "singer", c.SingerId)
+	}
+	if !c.EndTime.After(c.StartTime) {
+		return constraintError(sqlStateCheckViolation, "chk_end_time_after_start_time", "end time %v of concert %s is not after start time %v", c.EndTime, c.ID, c.StartTime)
+	}
+	return nil
+}
+
+// sortedSingers returns the singers that match, ordered by less.
+func (d *memoryData) sortedSingers(match func(s *Singer) bool, less func(a, b *Singer) bool) []*Singer {
+	singers := []*Singer{}
+	for _, s := range d.singers {
+		if match(s) {
+			singers = append(singers, copySinger(s))
+		}
+	}
+	sort.Slice(singers, func(i, j int) bool { return less(singers[i], singers[j]) })
+	return singers
+}
+
+func (d *memoryData) sortedAlbums(match func(a *Album) bool, less func(a, b *Album) bool) []*Album {
+	albums := []*Album{}
+	for _, a := range d.albums {
+		if match(a) {
+			albums = append(albums, copyAlbum(a))
+		}
+	}
+	sort.Slice(albums, func(i, j int) bool { return less(albums[i], albums[j]) })
+	return albums
+}
+
+func (d *memoryData) sortedVenues(match func(v *Venue) bool, less func(a, b *Venue) bool) []*Venue {
+	venues := []*Venue{}
+	for _, v := range d.venues {
+		if match(v) {
+			venues = append(venues, copyVenue(v))
+		}
+	}
+	sort.Slice(venues, func(i, j int) bool { return less(venues[i], venues[j]) })
+	return venues
+}
+
+// sortedTracks returns the tracks that match ordered by album and track number.
+func (d *memoryData) sortedTracks(match func(t *Track) bool) []*Track {
+	tracks := []*Track{}
+	for _, t := range d.tracks {
+		if match(t) {
+			tracks = append(tracks, copyTrack(t))
+		}
+	}
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].ID != tracks[j].ID {
+			return tracks[i].ID < tracks[j].ID
+		}
+		return tracks[i].TrackNumber < tracks[j].TrackNumber
+	})
+	return tracks
+}
+
+// sortedConcerts returns the concerts that match ordered by start time.
+func (d *memoryData) sortedConcerts(match func(c *Concert) bool) []*Concert {
+	concerts := []*Concert{}
+	for _, c := range d.concerts {
+		if match(c) {
+			concerts = append(concerts, copyConcert(c))
+		}
+	}
+	sort.Slice(concerts, func(i, j int) bool {
+		if !concerts[i].StartTime.Equal(concerts[j].StartTime) {
+			return concerts[i].StartTime.Before(concerts[j].StartTime)
+		}
+		return concerts[i].ID < concerts[j].ID
+	})
+	return concerts
+}
+
+func byId[T any](id func(T) string) func(a, b T) bool {
+	return func(a, b T) bool { return id(a) < id(b) }
+}
+
+func (r *memoryRepository) CreateSinger(ctx context.Context, singer *Singer) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.singers[singer.ID]; ok {
+			return duplicateKeyError("singers", singer.ID)
+		}
+		setCreateTimes(&singer.BaseModel, time.Now())
+		singer.FullName = fullName(singer)
+		d.singers[singer.ID] = copySinger(singer)
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateSinger(ctx context.Context, id string, updates map[string]interface{}) (*Singer, error) {
+	var singer *Singer
+	err := r.write(ctx, func(d *memoryData) error {
+		existing, ok := d.singers[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		singer = copySinger(existing)
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := setColumns(singer, updates); err != nil {
+			return err
+		}
+		singer.ID, singer.UpdatedAt, singer.FullName = id, time.Now(), fullName(singer)
+		d.singers[id] = copySinger(singer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return singer, nil
+}
+
+func (r *memoryRepository) DeleteSinger(ctx context.Context, id string) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.singers[id]; !ok {
+			return gorm.ErrRecordNotFound
+		}
+		for _, a := range d.albums {
+			if a.SingerId == id {
+				return stillReferencedError("fk_albums_singers", "singer", id)
+			}
+		}
+		for _, c := range d.concerts {
+			if c.SingerId == id {
+				return stillReferencedError("fk_concerts_singers", "singer", id)
+			}
+		}
+		delete(d.singers, id)
+		return nil
+	})
+}
+
+func (r *memoryRepository) GetSinger(ctx context.Context, id string) (*Singer, error) {
+	var singer *Singer
+	err := r.read(ctx, func(d *memoryData) error {
+		s, ok := d.singers[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		singer = copySinger(s)
+		return nil
+	})
+	return singer, err
+}
+
+func (r *memoryRepository) ListSingers(ctx context.Context, page Page) ([]*Singer, error) {
+	var singers []*Singer
+	err := r.read(ctx, func(d *memoryData) error {
+		singers = paginateSlice(d.sortedSingers(func(*Singer) bool { return true }, func(a, b *Singer) bool {
+			if a.LastName != b.LastName {
+				return a.LastName < b.LastName
+			}
+			return a.ID < b.ID
+		}), page)
+		return nil
+	})
+	return singers, err
+}
+
+func (r *memoryRepository) SingersByIds(ctx context.Context, ids []string) ([]*Singer, error) {
+	var singers []*Singer
+	set := stringSet(ids)
+	err := r.read(ctx, func(d *memoryData) error {
+		singers = d.sortedSingers(func(s *Singer) bool { return set[s.ID] }, byId(func(s *Singer) string { return s.ID }))
+		return nil
+	})
+	return singers, err
+}
+
+func (r *memoryRepository) CreateAlbum(ctx context.Context, album *Album) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.albums[album.ID]; ok {
+			return duplicateKeyError("albums", album.ID)
+		}
+		if err := d.checkAlbum(album); err != nil {
+			return err
+		}
+		setCreateTimes(&album.BaseModel, time.Now())
+		d.albums[album.ID] = copyAlbum(album)
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateAlbum(ctx context.Context, id string, updates map[string]interface{}) (*Album, error) {
+	var album *Album
+	err := r.write(ctx, func(d *memoryData) error {
+		existing, ok := d.albums[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		album = copyAlbum(existing)
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := setColumns(album, updates); err != nil {
+			return err
+		}
+		album.ID, album.UpdatedAt = id, time.Now()
+		if err := d.checkAlbum(album); err != nil {
+			return err
+		}
+		d.albums[id] = copyAlbum(album)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return album, nil
+}
+
+func (r *memoryRepository) DeleteAlbum(ctx context.Context, id string) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.albums[id]; !ok {
+			return gorm.ErrRecordNotFound
+		}
+		delete(d.albums, id)
+		for key := range d.tracks {
+			if key.albumId == id {
+				delete(d.tracks, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryRepository) ListAlbums(ctx context.Context, singerId string, page Page) ([]*Album, error) {
+	var albums []*Album
+	err := r.read(ctx, func(d *memoryData) error {
+		albums = paginateSlice(d.sortedAlbums(func(a *Album) bool { return singerId == "" || a.SingerId == singerId }, albumsByTitle), page)
+		return nil
+	})
+	return albums, err
+}
+
+func albumsByTitle(a, b *Album) bool {
+	if a.Title != b.Title {
+		return a.Title < b.Title
+	}
+	return a.ID < b.ID
+}
+
+func (r *memoryRepository) AlbumsByIds(ctx context.Context, ids []string) ([]*Album, error) {
+	var albums []*Album
+	set := stringSet(ids)
+	err := r.read(ctx, func(d *memoryData) error {
+		albums = d.sortedAlbums(func(a *Album) bool { return set[a.ID] }, byId(func(a *Album) string { return a.ID }))
+		return nil
+	})
+	return albums, err
+}
+
+func (r *memoryRepository) AlbumsBySingerIds(ctx context.Context, singerIds []string) ([]*Album, error) {
+	var albums []*Album
+	set := stringSet(singerIds)
+	err := r.read(ctx, func(d *memoryData) error {
+		albums = d.sortedAlbums(func(a *Album) bool { return set[a.SingerId] }, albumsByTitle)
+		return nil
+	})
+	return albums, err
+}
+
+func (r *memoryRepository) AlbumsOfSinger(ctx context.Context, singerId string) ([]*Album, error) {
+	var albums []*Album
+	err := r.read(ctx, func(d *memoryData) error {
+		albums = d.sortedAlbums(func(a *Album) bool { return a.SingerId == singerId }, byId(func(a *Album) string { return a.ID }))
+		for _, a := range albums {
+			a.Singer = *copySinger(d.singers[a.SingerId])
+			for _, t := range d.sortedTracks(func(t *Track) bool { return t.ID == a.ID }) {
+				a.Tracks = append(a.Tracks, *t)
+			}
+		}
+		return nil
+	})
+	return albums, err
+}
+
+func (r *memoryRepository) CreateTracks(ctx context.Context, tracks []*Track) error {
+	return r.write(ctx, func(d *memoryData) error {
+		now := time.Now()
+		for _, t := range tracks {
+			key := trackKey{albumId: t.ID, trackNumber: t.TrackNumber}
+			if _, ok := d.tracks[key]; ok {
+				return duplicateKeyError("tracks", fmt.Sprintf("(%s, %d)", t.ID, t.TrackNumber))
+			}
+			// Tracks are interleaved in albums, so the album must exist.
+			if _, ok := d.albums[t.ID]; !ok {
+				return missingReferenceError("tracks_interleave_albums", "album", t.ID)
+			}
+			setCreateTimes(&t.BaseModel, now)
+			d.tracks[key] = copyTrack(t)
+		}
+		return nil
+	})
+}
+
+func (r *memoryRepository) TracksByAlbumIds(ctx context.Context, albumIds []string) ([]*Track, error) {
+	var tracks []*Track
+	set := stringSet(albumIds)
+	err := r.read(ctx, func(d *memoryData) error {
+		tracks = d.sortedTracks(func(t *Track) bool { return set[t.ID] })
+		return nil
+	})
+	return tracks, err
+}
+
+func (r *memoryRepository) CreateVenue(ctx context.Context, venue *Venue) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.venues[venue.ID]; ok {
+			return duplicateKeyError("venues", venue.ID)
+		}
+		setCreateTimes(&venue.BaseModel, time.Now())
+		d.venues[venue.ID] = copyVenue(venue)
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateVenue(ctx context.Context, id string, updates map[string]interface{}) (*Venue, error) {
+	var venue *Venue
+	err := r.write(ctx, func(d *memoryData) error {
+		existing, ok := d.venues[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		venue = copyVenue(existing)
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := setColumns(venue, updates); err != nil {
+			return err
+		}
+		venue.ID, venue.UpdatedAt = id, time.Now()
+		d.venues[id] = copyVenue(venue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+func (r *memoryRepository) DeleteVenue(ctx context.Context, id string) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.venues[id]; !ok {
+			return gorm.ErrRecordNotFound
+		}
+		for _, c := range d.concerts {
+			if c.VenueId == id {
+				return stillReferencedError("fk_concerts_venues", "venue", id)
+			}
+		}
+		delete(d.venues, id)
+		return nil
+	})
+}
+
+func (r *memoryRepository) GetVenue(ctx context.Context, id string) (*Venue, error) {
+	var venue *Venue
+	err := r.read(ctx, func(d *memoryData) error {
+		v, ok := d.venues[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		venue = copyVenue(v)
+		return nil
+	})
+	return venue, err
+}
+
+func (r *memoryRepository) ListVenues(ctx context.Context, page Page) ([]*Venue, error) {
+	var venues []*Venue
+	err := r.read(ctx, func(d *memoryData) error {
+		venues = paginateSlice(d.sortedVenues(func(*Venue) bool { return true }, func(a, b *Venue) bool {
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return a.ID < b.ID
+		}), page)
+		return nil
+	})
+	return venues, err
+}
+
+func (r *memoryRepository) VenuesByIds(ctx context.Context, ids []string) ([]*Venue, error) {
+	var venues []*Venue
+	set := stringSet(ids)
+	err := r.read(ctx, func(d *memoryData) error {
+		venues = d.sortedVenues(func(v *Venue) bool { return set[v.ID] }, byId(func(v *Venue) string { return v.ID }))
+		return nil
+	})
+	return venues, err
+}
+
+func (r *memoryRepository) CreateConcert(ctx context.Context, concert *Concert) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.concerts[concert.ID]; ok {
+			return duplicateKeyError("concerts", concert.ID)
+		}
+		if err := d.checkConcert(concert); err != nil {
+			return err
+		}
+		setCreateTimes(&concert.BaseModel, time.Now())
+		d.concerts[concert.ID] = copyConcert(concert)
+		return nil
+	})
+}
+
+func (r *memoryRepository) UpdateConcert(ctx context.Context, id string, updates map[string]interface{}) (*Concert, error) {
+	var concert *Concert
+	err := r.write(ctx, func(d *memoryData) error {
+		existing, ok := d.concerts[id]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		concert = copyConcert(existing)
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := setColumns(concert, updates); err != nil {
+			return err
+		}
+		concert.ID, concert.UpdatedAt = id, time.Now()
+		if err := d.checkConcert(concert); err != nil {
+			return err
+		}
+		d.concerts[id] = copyConcert(concert)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return concert, nil
+}
+
+func (r *memoryRepository) DeleteConcert(ctx context.Context, id string) error {
+	return r.write(ctx, func(d *memoryData) error {
+		if _, ok := d.concerts[id]; !ok {
+			return gorm.ErrRecordNotFound
+		}
+		delete(d.concerts, id)
+		return nil
+	})
+}
+
+func (r *memoryRepository) FindConcerts(ctx context.Context, filter ConcertFilter, withAssociations bool) ([]*Concert, error) {
+	var concerts []*Concert
+	err := r.read(ctx, func(d *memoryData) error {
+		concerts = paginateSlice(d.sortedConcerts(func(c *Concert) bool {
+			return (filter.From.IsZero() || c.EndTime.After(filter.From)) &&
+				(filter.To.IsZero() || c.StartTime.Before(filter.To)) &&
+				(filter.VenueId == "" || c.VenueId == filter.VenueId) &&
+				(filter.SingerId == "" || c.SingerId == filter.SingerId)
+		}), Page{Limit: filter.Limit, Offset: filter.Offset})
+		if withAssociations {
+			for _, c := range concerts {
+				c.Venue, c.Singer = *copyVenue(d.venues[c.VenueId]), *copySinger(d.singers[c.SingerId])
+			}
+		}
+		return nil
+	})
+	return concerts, err
+}
+
+func (r *memoryRepository) ConcertsBySingerIds(ctx context.Context, singerIds []string) ([]*Concert, error) {
+	var concerts []*Concert
+	set := stringSet(singerIds)
+	err := r.read(ctx, func(d *memoryData) error {
+		concerts = d.sortedConcerts(func(c *Concert) bool { return set[c.SingerId] })
+		return nil
+	})
+	return concerts, err
+}
+
+func (r *memoryRepository) ConcertsByVenueIds(ctx context.Context, venueIds []string) ([]*Concert, error) {
+	var concerts []*Concert
+	set := stringSet(venueIds)
+	err := r.read(ctx, func(d *memoryData) error {
+		concerts = d.sortedConcerts(func(c *Concert) bool { return set[c.VenueId] })
+		return nil
+	})
+	return concerts, err
+}
+
+func (r *memoryRepository) SingerStats(ctx context.Context) ([]*SingerStats, error) {
+	stats := []*SingerStats{}
+	err := r.read(ctx, func(d *memoryData) error {
+		bySinger := map[string]*SingerStats{}
+		for _, s := range d.singers {
+			st := &SingerStats{SingerId: s.ID, FullName: s.FullName}
+			bySinger[s.ID] = st
+			stats = append(stats, st)
+		}
+		for _, a := range d.albums {
+			st := bySinger[a.SingerId]
+			st.AlbumCount++
+			if a.MarketingBudget.Valid {
+				st.TotalBudget = decimal.NullDecimal{Decimal: st.TotalBudget.Decimal.Add(a.MarketingBudget.Decimal), Valid: true}
+				st.BudgetCount++
+			}
+		}
+		for _, t := range d.tracks {
+			bySinger[d.albums[t.ID].SingerId].TrackCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].FullName != stats[j].FullName {
+			return stats[i].FullName < stats[j].FullName
+		}
+		return stats[i].SingerId < stats[j].SingerId
+	})
+	setAverageBudgets(stats)
+	return stats, nil
+}
+
+func (r *memoryRepository) SampleRateStats(ctx context.Context, bucketWidth float64) (*SampleRateStats, error) {
+	stats := &SampleRateStats{BucketWidth: bucketWidth, Buckets: []SampleRateBucket{}}
+	err := r.read(ctx, func(d *memoryData) error {
+		buckets := map[float64]int64{}
+		sum := 0.0
+		for _, t := range d.tracks {
+			if stats.TrackCount == 0 || t.SampleRate < stats.Min {
+				stats.Min = t.SampleRate
+			}
+			if stats.TrackCount == 0 || t.SampleRate > stats.Max {
+				stats.Max = t.SampleRate
+			}
+			stats.TrackCount++
+			sum += t.SampleRate
+			buckets[math.Floor(t.SampleRate/bucketWidth)*bucketWidth]++
+		}
+		if stats.TrackCount > 0 {
+			stats.Average = sum / float64(stats.TrackCount)
+		}
+		for lowerBound, count := range buckets {
+			stats.Buckets = append(stats.Buckets, SampleRateBucket{LowerBound: lowerBound, TrackCount: count})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats.Buckets, func(i, j int) bool { return stats.Buckets[i].LowerBound < stats.Buckets[j].LowerBound })
+	return stats, nil
+}
+
+func (r *memoryRepository) AlbumsPerDecade(ctx context.Context) ([]*DecadeStats, error) {
+	stats := []*DecadeStats{}
+	err := r.read(ctx, func(d *memoryData) error {
+		byDecade := map[int64]*DecadeStats{}
+		for _, a := range d.albums {
+			// Albums without a release date are stored as NULL and are not counted by the database either.
+			if time.Time(a.ReleaseDate).IsZero() {
+				continue
+			}
+			decade := int64(math.Floor(float64(time.Time(a.ReleaseDate).Year())/10)) * 10
+			st, ok := byDecade[decade]
+			if !ok {
+				st = &DecadeStats{Decade: decade}
+				byDecade[decade] = st
+				stats = append(stats, st)
+			}
+			st.AlbumCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Decade < stats[j].Decade })
+	return stats, nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+var (
+	testConcertStart = time.Date(2023, 2, 1, 20, 0, 0, 0, time.UTC)
+	testConcertEnd   = testConcertStart.Add(2 * time.Hour)
+)
+
+// seedMemoryRepository stores singer s1 with album a1 and its tracks, and concert c1 of s1 at venue v1.
+func seedMemoryRepository(t *testing.T) *memoryRepository {
+	t.Helper()
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	for _, err := range []error{
+		repo.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: "s1"}, LastName: "Smith"}),
+		repo.CreateVenue(ctx, &Venue{BaseModel: BaseModel{ID: "v1"}, Name: "Hall"}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a1"}, Title: "Songs", SingerId: "s1"}),
+		repo.CreateTracks(ctx, []*Track{{BaseModel: BaseModel{ID: "a1"}, TrackNumber: 1}, {BaseModel: BaseModel{ID: "a1"}, TrackNumber: 2}}),
+		repo.CreateConcert(ctx, &Concert{BaseModel: BaseModel{ID: "c1"}, VenueId: "v1", SingerId: "s1",
+			StartTime: testConcertStart, EndTime: testConcertEnd}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// TestMemoryRepositoryErrorsMatchSpanner checks that the memory repository returns the same errors as the GORM
+// repository for operations that violate the data model. The GORM repository gets the errors that Cloud Spanner
+// returns through PGAdapter from a fake server.
+func TestMemoryRepositoryErrorsMatchSpanner(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		op   func(ctx context.Context, repo MusicRepository) error
+		// fragment is the statement that fails in Cloud Spanner with spannerErr.
+		fragment   string
+		spannerErr pgfake.Result
+		// code is the SQLSTATE of the error, or empty for gorm.ErrRecordNotFound.
+		code string
+	}{
+		{
+			name: "album of a missing singer",
+			op: func(ctx context.Context, repo MusicRepository) error {
+				return repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a2"}, SingerId: "s2"})
+			},
+			fragment:   `INSERT INTO "albums"`,
+			spannerErr: pgfake.Error("23503", "Foreign key constraint `fk_albums_singers` is violated on table `albums`. Cannot find referenced values in singers(id)."),
+			code:       sqlStateForeignKeyViolation,
+		},
+		{
+			name: "concert at a missing venue",
+			op: func(ctx context.Context, repo MusicRepository) error {
+				return repo.CreateConcert(ctx, &Concert{BaseModel: BaseModel{ID: "c2"}, VenueId: "v2", SingerId: "s1",
+					StartTime: testConcertStart, EndTime: testConcertEnd})
+			},
+			fragment:   `INSERT INTO "concerts"`,
+			spannerErr: pgfake.Error("23503", "Foreign key constraint `fk_concerts_venues` is violated on table `concerts`. Cannot find referenced values in venues(id)."),
+			code:       sqlStateForeignKeyViolation,
+		},
+		{
+			name: "concert that ends before it starts",
+			op: func(ctx context.Context, repo MusicRepository) error {
+				return repo.CreateConcert(ctx, &Concert{BaseModel: BaseModel{ID: "c2"}, VenueId: "v1", SingerId: "s1",
+					StartTime: testConcertEnd, EndTime: testConcertStart})
+			},
+			fragment:   `INSERT INTO "concerts"`,
+			spannerErr: pgfake.Error("23514", "Check constraint `concerts`.`chk_end_time_after_start_time` is violated for key (c2)"),
+			code:       sqlStateCheckViolation,
+		},
+		{
+			name: "update of a concert that ends when it starts",
+			op: func(ctx context.Context, repo MusicRepository) error {
+				_, err := repo.UpdateConcert(ctx, "c1", map[string]interface{}{"end_time": testConcertStart})
+				return err
+			},
+			fragment:   `UPDATE "concerts"`,
+			spannerErr: pgfake.Error("23514", "Check constraint `concerts`.`chk_end_time_after_start_time` is violated for key (c1)"),
+			code:       sqlStateCheckViolation,
+		},
+		{
+			name:       "singer with albums",
+			op:         func(ctx context.Context, repo MusicRepository) error { return repo.DeleteSinger(ctx, "s1") },
+			fragment:   `DELETE FROM "singers"`,
+			spannerErr: pgfake.Error("23503", "Foreign key constraint violation when deleting or updating referenced row(s): referencing row(s) found in table `albums`."),
+			code:       sqlStateForeignKeyViolation,
+		},
+		{
+			name:       "venue with concerts",
+			op:         func(ctx context.Context, repo MusicRepository) error { return repo.DeleteVenue(ctx, "v1") },
+			fragment:   `DELETE FROM "venues"`,
+			spannerErr: pgfake.Error("23503", "Foreign key constraint violation when deleting or updating referenced row(s): referencing row(s) found in table `concerts`."),
+			code:       sqlStateForeignKeyViolation,
+		},
+		{
+			name: "duplicate singer",
+			op: func(ctx context.Context, repo MusicRepository) error {
+				return repo.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: "s1"}, LastName: "Jones"})
+			},
+			fragment:   `INSERT INTO "singers"`,
+			spannerErr: pgfake.Error("23505", "Row [s1] in table singers already exists"),
+			code:       sqlStateUniqueViolation,
+		},
+		{
+			name:       "missing concert",
+			op:         func(ctx context.Context, repo MusicRepository) error { return repo.DeleteConcert(ctx, "c2") },
+			fragment:   `DELETE FROM "concerts"`,
+			spannerErr: pgfake.Tag("DELETE 0"),
+		},
+	} {
+		server, db := newFakeDb(t)
+		server.On(tc.fragment, tc.spannerErr)
+		for name, repo := range map[string]MusicRepository{"memory": seedMemoryRepository(t), "gorm": newGormRepository(db)} {
+			err := tc.op(context.Background(), repo)
+			var pgErr *pgconn.PgError
+			switch {
+			case tc.code == "" && !errors.Is(err, gorm.ErrRecordNotFound):
+				t.Errorf("%s, %s repository: got %v, want gorm.ErrRecordNotFound", tc.name, name, err)
+			case tc.code != "" && (!errors.As(err, &pgErr) || pgErr.Code != tc.code):
+				t.Errorf("%s, %s repository: got %v, want SQLSTATE %s", tc.name, name, err, tc.code)
+			}
+		}
+	}
+}
+
+func TestMemoryRepositoryCascadesDeletesToTracks(t *testing.T) {
+	ctx := context.Background()
+	repo := seedMemoryRepository(t)
+	if err := repo.DeleteAlbum(ctx, "a1"); err != nil {
+		t.Fatal(err)
+	}
+	if tracks, err := repo.TracksByAlbumIds(ctx, []string{"a1"}); err != nil || len(tracks) != 0 {
+		t.Errorf("got %d tracks, %v after the album was deleted, want none", len(tracks), err)
+	}
+	// The singer is no longer referenced by albums, but still by the concert.
+	var pgErr *pgconn.PgError
+	if err := repo.DeleteSinger(ctx, "s1"); !errors.As(err, &pgErr) || pgErr.ConstraintName != "fk_concerts_singers" {
+		t.Errorf("got %v, want a violation of fk_concerts_singers", err)
+	}
+
+	// Cloud Spanner deletes the tracks of the interleaved table itself, so the GORM repository only deletes the album.
+	server, db := newFakeDb(t)
+	server.On(`DELETE FROM "albums"`, pgfake.Tag("DELETE 1"))
+	if err := newGormRepository(db).DeleteAlbum(ctx, "a1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(statementsWith(server, `"tracks"`)); got != 0 {
+		t.Errorf("got %d statements on tracks, want 0", got)
+	}
+}
+
+func TestRESTHandlersWithMemoryRepository(t *testing.T) {
+	cfg := defaultConfig()
+	authn, err := newAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := MusicDbOperation{repo: seedMemoryRepository(t), cfg: cfg, state: &serverState{}, tenants: newTenantRouter(cfg, zerolog.Nop())}
+	r, err := m.newRouter(authn, newRateLimiter(cfg), zerolog.Nop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+
+	w := serve(http.MethodPost, "/api/register-singer-with-album", `{"first_name": "Alice", "last_name": "Jones", "album_name": "Hits"}`)
+	var ids SingerAlbumIds
+	if err := json.Unmarshal(w.Body.Bytes(), &ids); w.Code != http.StatusOK || err != nil || ids.SingerId == "" {
+		t.Fatalf("register: got status %d, %s", w.Code, w.Body)
+	}
+	w = serve(http.MethodGet, "/api/get-albums-of-singerid/"+ids.SingerId, "")
+	var albums []*Album
+	if err := json.Unmarshal(w.Body.Bytes(), &albums); w.Code != http.StatusOK || err != nil || len(albums) != 1 ||
+		albums[0].ID != ids.AlbumId || len(albums[0].Tracks) == 0 {
+		t.Errorf("albums of the new singer: got status %d, %s", w.Code, w.Body)
+	}
+
+	for _, tc := range []struct {
+		method, target, body string
+		want                 int
+		contains             string
+	}{
+		{http.MethodPost, "/api/register-singer-with-album", `{"last_name": `, http.StatusBadRequest, "invalid parameters"},
+		{http.MethodGet, "/api/get-albums-of-singerid/s2", "", http.StatusNotFound, "user not found"},
+		{http.MethodGet, "/api/concerts?venue_id=v1", "", http.StatusOK, `"ID":"c1"`},
+		{http.MethodGet, "/api/concerts?limit=0", "", http.StatusBadRequest, "limit must be between"},
+		{http.MethodGet, "/api/venues/v1/concerts.ics", "", http.StatusOK, "BEGIN:VEVENT"},
+		{http.MethodGet, "/api/venues/v2/concerts.ics", "", http.StatusNotFound, "venue not found"},
+		{http.MethodGet, "/api/singers/s2/concerts.ics", "", http.StatusNotFound, "singer not found"},
+	} {
+		w := serve(tc.method, tc.target, tc.body)
+		if w.Code != tc.want || !strings.Contains(w.Body.String(), tc.contains) {
+			t.Errorf("%s %s: got status %d, %s, want %d with %q", tc.method, tc.target, w.Code, w.Body, tc.want, tc.contains)
+		}
+	}
+}
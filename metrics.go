@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route pattern, method, status code and tenant.",
+	}, []string{"route", "method", "status", "tenant"})
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route pattern, method and tenant.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "tenant"})
+
+	gormStatementDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gorm_statement_duration_seconds",
+		Help:    "Latency of the statements executed by GORM by operation and tenant.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "tenant"})
+	gormStatementErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gorm_statement_errors_total",
+		Help: "Number of statements executed by GORM that failed, by operation and tenant.",
+	}, []string{"operation", "tenant"})
+
+	dbTransactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_transactions_total",
+		Help: "Number of finished transactions by outcome (commit or rollback) and tenant. Failed commits count as rollback.",
+	}, []string{"outcome", "tenant"})
+	dbTransactionRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "db_transaction_retries_total",
+		Help: "Number of transactions that were retried after they were aborted by Cloud Spanner or lost their connection.",
+	})
+	dbStatementRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "db_statement_retries_total",
+		Help: "Number of statements outside transactions that were retried after a connection failure.",
+	})
+)
+
+// metricsMiddleware records the count and latency of HTTP requests. Requests are labeled with the route pattern
+// instead of the path, so that path parameters such as IDs do not create a time series per value.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+
+		route := chi.RouteContext(r.Context()).RoutePattern()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		tenant := tenantName(r.Context())
+		httpRequests.WithLabelValues(route, r.Method, strconv.Itoa(status), tenant).Inc()
+		httpRequestDuration.WithLabelValues(route, r.Method, tenant).Observe(time.Since(start).Seconds())
+	})
+}
+
+// registerDbStatsCollector exports the sql.DBStats of the connection pool of db, such as the number of open, in-use
+// and idle connections and the time spent waiting for a connection.
+func registerDbStatsCollector(db *gorm.DB, dbName string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return prometheus.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
+
+// gormMetrics is a GORM plugin that records the latency and errors of each statement of the database of a tenant.
+type gormMetrics struct {
+	tenant string
+}
+
+const gormMetricsStartKey = "metrics:start"
+
+func (gormMetrics) Name() string {
+	return "metrics"
+}
+
+func (p gormMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", p.before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", p.after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", p.before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", p.after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", p.before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", p.after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", p.after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", p.before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", p.after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", p.after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gormMetrics) before(db *gorm.DB) {
+	db.InstanceSet(gormMetricsStartKey, time.Now())
+}
+
+func (p gormMetrics) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if start, ok := db.InstanceGet(gormMetricsStartKey); ok {
+			gormStatementDuration.WithLabelValues(operation, p.tenant).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			gormStatementErrors.WithLabelValues(operation, p.tenant).Inc()
+		}
+	}
+}
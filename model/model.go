@@ -0,0 +1,106 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package model defines the data model of the music catalog. The server stores the models with GORM and encodes
+// them in the responses of the HTTP API, and the client decodes the responses into the same types.
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// SingerAlbumInfo is the request body of POST /api/register-singer-with-album.
+type SingerAlbumInfo struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	AlbumName string `json:"album_name"`
+}
+
+// SingerAlbumIds is the response body of POST /api/register-singer-with-album.
+type SingerAlbumIds struct {
+	SingerId string `json:"singer_id"`
+	AlbumId  string `json:"album_id"`
+}
+
+// ErrorResponse is the body of responses with an error status.
+type ErrorResponse struct {
+	Error string `json:"ERROR"`
+}
+
+// BaseModel is embedded in all other models to add common database fields.
+type BaseModel struct {
+	// ID is the primary key of each model. The ID is generated client side as a UUID.
+	// Adding the `primaryKey` annotation is redundant for most models, as gorm will assume that the column with name ID
+	// is the primary key. This is however not redundant for models that add additional primary key columns, such as
+	// child tables in interleaved table hierarchies, as a missing primary key annotation here would then cause the
+	// primary key column defined on the child table to be the only primary key column.
+	ID string `gorm:"primaryKey;autoIncrement:false"`
+	// CreatedAt and UpdatedAt are managed automatically by gorm.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Singer struct {
+	BaseModel
+	FirstName sql.NullString
+	LastName  string
+	// FullName is generated by the database. The '->' marks this a read-only field. It has no `default:(-)`
+	// annotation, as gorm would then read the value back using a RETURNING clause, which Cloud Spanner does not support
+	// for generated columns. The singer is reloaded after it is created instead.
+	FullName string `gorm:"->;type:GENERATED ALWAYS AS (coalesce(concat(first_name,' '::varchar,last_name))) STORED;"`
+	Active   bool
+	Albums   []Album
+}
+
+type Album struct {
+	BaseModel
+	Title           string
+	MarketingBudget decimal.NullDecimal
+	ReleaseDate     datatypes.Date
+	CoverPicture    []byte
+	SingerId        string
+	Singer          Singer
+	Tracks          []Track `gorm:"foreignKey:ID"`
+}
+
+// Track is interleaved in Album. The ID column is both the first part of the primary key of Track, and a
+// reference to the Album that owns the Track.
+type Track struct {
+	BaseModel
+	TrackNumber int64 `gorm:"primaryKey;autoIncrement:false"`
+	Title       string
+	SampleRate  float64
+	Album       Album `gorm:"foreignKey:ID"`
+}
+
+type Venue struct {
+	BaseModel
+	Name        string
+	Description string
+}
+
+type Concert struct {
+	BaseModel
+	Name      string
+	Venue     Venue
+	VenueId   string
+	Singer    Singer
+	SingerId  string
+	StartTime time.Time
+	EndTime   time.Time
+}
@@ -0,0 +1,303 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: music.proto
+
+package musicpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MusicServiceClient is the client API for MusicService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MusicServiceClient interface {
+	// Creates a singer and an album with random tracks in one transaction.
+	// Equivalent to POST /api/register-singer-with-album.
+	RegisterSingerWithAlbum(ctx context.Context, in *RegisterSingerWithAlbumRequest, opts ...grpc.CallOption) (*RegisterSingerWithAlbumResponse, error)
+	// Returns all albums of a singer including their tracks.
+	// Equivalent to GET /api/get-albums-of-singerid/{singer_id}.
+	GetAlbumsOfSinger(ctx context.Context, in *GetAlbumsOfSingerRequest, opts ...grpc.CallOption) (*GetAlbumsOfSingerResponse, error)
+	// Returns the concerts that overlap with a time window.
+	// Equivalent to GET /api/concerts.
+	ListConcerts(ctx context.Context, in *ListConcertsRequest, opts ...grpc.CallOption) (*ListConcertsResponse, error)
+	// Equivalent to GET /api/stats/singers.
+	GetSingerStats(ctx context.Context, in *GetSingerStatsRequest, opts ...grpc.CallOption) (*GetSingerStatsResponse, error)
+	// Equivalent to GET /api/stats/sample-rates.
+	GetSampleRateStats(ctx context.Context, in *GetSampleRateStatsRequest, opts ...grpc.CallOption) (*SampleRateStats, error)
+	// Equivalent to GET /api/stats/albums-per-decade.
+	GetAlbumsPerDecade(ctx context.Context, in *GetAlbumsPerDecadeRequest, opts ...grpc.CallOption) (*GetAlbumsPerDecadeResponse, error)
+}
+
+type musicServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMusicServiceClient(cc grpc.ClientConnInterface) MusicServiceClient {
+	return &musicServiceClient{cc}
+}
+
+func (c *musicServiceClient) RegisterSingerWithAlbum(ctx context.Context, in *RegisterSingerWithAlbumRequest, opts ...grpc.CallOption) (*RegisterSingerWithAlbumResponse, error) {
+	out := new(RegisterSingerWithAlbumResponse)
+	err := c.cc.Invoke(ctx, "/music.v1.MusicService/RegisterSingerWithAlbum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *musicServiceClient) GetAlbumsOfSinger(ctx context.Context, in *GetAlbumsOfSingerRequest, opts ...grpc.CallOption) (*GetAlbumsOfSingerResponse, error) {
+	out := new(GetAlbumsOfSingerResponse)
+	err := c.cc.Invoke(ctx, "/music.v1.MusicService/GetAlbumsOfSinger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *musicServiceClient) ListConcerts(ctx context.Context, in *ListConcertsRequest, opts ...grpc.CallOption) (*ListConcertsResponse, error) {
+	out := new(ListConcertsResponse)
+	err := c.cc.Invoke(ctx, "/music.v1.MusicService/ListConcerts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *musicServiceClient) GetSingerStats(ctx context.Context, in *GetSingerStatsRequest, opts ...grpc.CallOption) (*GetSingerStatsResponse, error) {
+	out := new(GetSingerStatsResponse)
+	err := c.cc.Invoke(ctx, "/music.v1.MusicService/GetSingerStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *musicServiceClient) GetSampleRateStats(ctx context.Context, in *GetSampleRateStatsRequest, opts ...grpc.CallOption) (*SampleRateStats, error) {
+	out := new(SampleRateStats)
+	err := c.cc.Invoke(ctx, "/music.v1.MusicService/GetSampleRateStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *musicServiceClient) GetAlbumsPerDecade(ctx context.Context, in *GetAlbumsPerDecadeRequest, opts ...grpc.CallOption) (*GetAlbumsPerDecadeResponse, error) {
+	out := new(GetAlbumsPerDecadeResponse)
+	err := c.cc.Invoke(ctx, "/music.v1.MusicService/GetAlbumsPerDecade", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MusicServiceServer is the server API for MusicService service.
+// All implementations must embed UnimplementedMusicServiceServer
+// for forward compatibility
+type MusicServiceServer interface {
+	// Creates a singer and an album with random tracks in one transaction.
+	// Equivalent to POST /api/register-singer-with-album.
+	RegisterSingerWithAlbum(context.Context, *RegisterSingerWithAlbumRequest) (*RegisterSingerWithAlbumResponse, error)
+	// Returns all albums of a singer including their tracks.
+	// Equivalent to GET /api/get-albums-of-singerid/{singer_id}.
+	GetAlbumsOfSinger(context.Context, *GetAlbumsOfSingerRequest) (*GetAlbumsOfSingerResponse, error)
+	// Returns the concerts that overlap with a time window.
+	// Equivalent to GET /api/concerts.
+	ListConcerts(context.Context, *ListConcertsRequest) (*ListConcertsResponse, error)
+	// Equivalent to GET /api/stats/singers.
+	GetSingerStats(context.Context, *GetSingerStatsRequest) (*GetSingerStatsResponse, error)
+	// Equivalent to GET /api/stats/sample-rates.
+	GetSampleRateStats(context.Context, *GetSampleRateStatsRequest) (*SampleRateStats, error)
+	// Equivalent to GET /api/stats/albums-per-decade.
+	GetAlbumsPerDecade(context.Context, *GetAlbumsPerDecadeRequest) (*GetAlbumsPerDecadeResponse, error)
+	mustEmbedUnimplementedMusicServiceServer()
+}
+
+// UnimplementedMusicServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMusicServiceServer struct {
+}
+
+func (UnimplementedMusicServiceServer) RegisterSingerWithAlbum(context.Context, *RegisterSingerWithAlbumRequest) (*RegisterSingerWithAlbumResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSingerWithAlbum not implemented")
+}
+func (UnimplementedMusicServiceServer) GetAlbumsOfSinger(context.Context, *GetAlbumsOfSingerRequest) (*GetAlbumsOfSingerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlbumsOfSinger not implemented")
+}
+func (UnimplementedMusicServiceServer) ListConcerts(context.Context, *ListConcertsRequest) (*ListConcertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConcerts not implemented")
+}
+func (UnimplementedMusicServiceServer) GetSingerStats(context.Context, *GetSingerStatsRequest) (*GetSingerStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSingerStats not implemented")
+}
+func (UnimplementedMusicServiceServer) GetSampleRateStats(context.Context, *GetSampleRateStatsRequest) (*SampleRateStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSampleRateStats not implemented")
+}
+func (UnimplementedMusicServiceServer) GetAlbumsPerDecade(context.Context, *GetAlbumsPerDecadeRequest) (*GetAlbumsPerDecadeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlbumsPerDecade not implemented")
+}
+func (UnimplementedMusicServiceServer) mustEmbedUnimplementedMusicServiceServer() {}
+
+// UnsafeMusicServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MusicServiceServer will
+// result in compilation errors.
+type UnsafeMusicServiceServer interface {
+	mustEmbedUnimplementedMusicServiceServer()
+}
+
+func RegisterMusicServiceServer(s grpc.ServiceRegistrar, srv MusicServiceServer) {
+	s.RegisterService(&MusicService_ServiceDesc, srv)
+}
+
+func _MusicService_RegisterSingerWithAlbum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSingerWithAlbumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MusicServiceServer).RegisterSingerWithAlbum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/music.v1.MusicService/RegisterSingerWithAlbum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MusicServiceServer).RegisterSingerWithAlbum(ctx, req.(*RegisterSingerWithAlbumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MusicService_GetAlbumsOfSinger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlbumsOfSingerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MusicServiceServer).GetAlbumsOfSinger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/music.v1.MusicService/GetAlbumsOfSinger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MusicServiceServer).GetAlbumsOfSinger(ctx, req.(*GetAlbumsOfSingerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MusicService_ListConcerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConcertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MusicServiceServer).ListConcerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/music.v1.MusicService/ListConcerts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MusicServiceServer).ListConcerts(ctx, req.(*ListConcertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MusicService_GetSingerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSingerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MusicServiceServer).GetSingerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/music.v1.MusicService/GetSingerStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MusicServiceServer).GetSingerStats(ctx, req.(*GetSingerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MusicService_GetSampleRateStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSampleRateStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MusicServiceServer).GetSampleRateStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/music.v1.MusicService/GetSampleRateStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MusicServiceServer).GetSampleRateStats(ctx, req.(*GetSampleRateStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MusicService_GetAlbumsPerDecade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlbumsPerDecadeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MusicServiceServer).GetAlbumsPerDecade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/music.v1.MusicService/GetAlbumsPerDecade",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MusicServiceServer).GetAlbumsPerDecade(ctx, req.(*GetAlbumsPerDecadeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MusicService_ServiceDesc is the grpc.ServiceDesc for MusicService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MusicService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "music.v1.MusicService",
+	HandlerType: (*MusicServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterSingerWithAlbum",
+			Handler:    _MusicService_RegisterSingerWithAlbum_Handler,
+		},
+		{
+			MethodName: "GetAlbumsOfSinger",
+			Handler:    _MusicService_GetAlbumsOfSinger_Handler,
+		},
+		{
+			MethodName: "ListConcerts",
+			Handler:    _MusicService_ListConcerts_Handler,
+		},
+		{
+			MethodName: "GetSingerStats",
+			Handler:    _MusicService_GetSingerStats_Handler,
+		},
+		{
+			MethodName: "GetSampleRateStats",
+			Handler:    _MusicService_GetSampleRateStats_Handler,
+		},
+		{
+			MethodName: "GetAlbumsPerDecade",
+			Handler:    _MusicService_GetAlbumsPerDecade_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "music.proto",
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/go-chi/render"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/client"
+	"shin5ok/simple-gorm-with-cloud-spanner/model"
+)
+
+// The request and response bodies of the REST API are defined in the model package, which the client also uses,
+// so that the client and the server cannot disagree about them.
+type (
+	SingerAlbumInfo = model.SingerAlbumInfo
+	SingerAlbumIds  = model.SingerAlbumIds
+	// ErrorResponse is the body that is written by errorRender.
+	ErrorResponse = model.ErrorResponse
+)
+
+// openAPIOperation describes one route of the chi router in the OpenAPI document.
+type openAPIOperation struct {
+	method      string
+	path        string
+	id          string
+	summary     string
+	params      []*openapi3.Parameter
+	request     interface{}
+	response    interface{}
+	contentType string
+	errors      []int
+	// statuses are other status codes than 200 that also return the response body.
+	statuses []int
+}
+
+func openAPIOperations() []openAPIOperation {
+	singerIdParam := openapi3.NewPathParameter("singerId").WithSchema(openapi3.NewStringSchema())
+	venueIdParam := openapi3.NewPathParameter("venueId").WithSchema(openapi3.NewStringSchema())
+	timeWindowParams := []*openapi3.Parameter{
+		openapi3.NewQueryParameter("from").WithSchema(openapi3.NewDateTimeSchema()).
+			WithDescription("Only return concerts that end after this time (RFC3339)."),
+		openapi3.NewQueryParameter("to").WithSchema(openapi3.NewDateTimeSchema()).
+			WithDescription("Only return concerts that start before this time (RFC3339)."),
+	}
+	pageParams := []*openapi3.Parameter{
+		openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema().WithMin(1).WithMax(maxConcertLimit)),
+		openapi3.NewQueryParameter("offset").WithSchema(openapi3.NewIntegerSchema().WithMin(0)),
+	}
+	concertParams := append(append([]*openapi3.Parameter{
+		openapi3.NewQueryParameter("venue_id").WithSchema(openapi3.NewStringSchema()),
+		openapi3.NewQueryParameter("singer_id").WithSchema(openapi3.NewStringSchema()),
+	}, timeWindowParams...), pageParams...)
+
+	return []openAPIOperation{
+		{method: http.MethodGet, path: "/ping", id: "ping", summary: "Returns pong if the server is running.",
+			response: map[string]string{}},
+		{method: http.MethodGet, path: "/healthz", id: "liveness", summary: "Reports whether the server process is alive.",
+			response: HealthReport{}},
+		{method: http.MethodGet, path: "/readyz", id: "readiness",
+			summary:  "Reports whether the server accepts new traffic and can reach the database, with the result of each check.",
+			response: HealthReport{}, statuses: []int{http.StatusServiceUnavailable}},
+		{method: http.MethodGet, path: "/metrics", id: "getMetrics", summary: "Returns the metrics in the Prometheus text format.",
+			response: "", contentType: "text/plain"},
+		{method: http.MethodGet, path: "/admin/config", id: "getConfig",
+			summary:  "Returns the effective configuration with secrets redacted. Requires the admin role.",
+			response: map[string]interface{}{}, contentType: "application/yaml"},
+		{method: http.MethodGet, path: "/admin/faults", id: "getFaults",
+			summary:  "Returns the fault injection configuration. Requires the admin role.",
+			response: FaultInjectionConfig{}},
+		{method: http.MethodPut, path: "/admin/faults", id: "setFaults",
+			summary: "Replaces the fault injection configuration of the instance until it restarts. Requires the admin role.",
+			request: FaultInjectionConfig{}, response: FaultInjectionConfig{}, errors: []int{http.StatusBadRequest}},
+		{method: http.MethodDelete, path: "/admin/faults", id: "deleteFaults",
+			summary:  "Disables fault injection and removes all rules. Requires the admin role.",
+			response: FaultInjectionConfig{}},
+		{method: http.MethodGet, path: "/openapi.json", id: "getOpenAPIDocument", summary: "Returns this OpenAPI document.",
+			response: map[string]interface{}{}},
+		{method: http.MethodGet, path: "/graphql", id: "queryGraphQL",
+			summary: "Executes a GraphQL query passed as query parameters. Mutations must be sent with POST.",
+			params: []*openapi3.Parameter{
+				openapi3.NewQueryParameter("query").WithSchema(openapi3.NewStringSchema()).WithRequired(true),
+				openapi3.NewQueryParameter("operationName").WithSchema(openapi3.NewStringSchema()),
+				openapi3.NewQueryParameter("variables").WithSchema(openapi3.NewStringSchema()).
+					WithDescription("The variables of the query as a JSON object."),
+			},
+			response: map[string]interface{}{}, errors: []int{http.StatusBadRequest, http.StatusMethodNotAllowed}},
+		{method: http.MethodPost, path: "/graphql", id: "executeGraphQL", summary: "Executes a GraphQL query or mutation.",
+			request: graphqlRequest{}, response: map[string]interface{}{}, errors: []int{http.StatusBadRequest}},
+		{method: http.MethodGet, path: "/api/get-albums-of-singerid/{singerId}", id: "getAlbumsOfSinger",
+			summary: "Returns all albums of a singer including the singer and the tracks of each album.",
+			params:  []*openapi3.Parameter{singerIdParam}, response: []*Album{},
+			errors: []int{http.StatusNotFound, http.StatusInternalServerError}},
+		{method: http.MethodPost, path: "/api/register-singer-with-album", id: "registerSingerWithAlbum",
+			summary: "Creates a singer and an album with random tracks.",
+			request: SingerAlbumInfo{}, response: SingerAlbumIds{},
+			errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{method: http.MethodGet, path: "/api/concerts", id: "listConcerts",
+			summary: "Returns the concerts that overlap with a time window, ordered by start time.",
+			params:  concertParams, response: []*Concert{},
+			errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{method: http.MethodGet, path: "/api/venues/{venueId}/concerts.ics", id: "exportVenueCalendar",
+			summary: "Exports the first 1000 concerts at a venue by start time as an iCalendar feed.",
+			params:  append([]*openapi3.Parameter{venueIdParam}, timeWindowParams...), response: "", contentType: "text/calendar",
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{method: http.MethodGet, path: "/api/singers/{singerId}/concerts.ics", id: "exportSingerCalendar",
+			summary: "Exports the first 1000 concerts of a singer by start time as an iCalendar feed.",
+			params:  append([]*openapi3.Parameter{singerIdParam}, timeWindowParams...), response: "", contentType: "text/calendar",
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{method: http.MethodGet, path: "/api/stats/singers", id: "getSingerStats",
+			summary:  "Returns the number of albums and tracks and the marketing budget per singer.",
+			response: []*SingerStats{}, errors: []int{http.StatusInternalServerError}},
+		{method: http.MethodGet, path: "/api/stats/sample-rates", id: "getSampleRateStats",
+			summary: "Returns the distribution of the sample rates of all tracks.",
+			params: []*openapi3.Parameter{
+				openapi3.NewQueryParameter("bucket_width").WithSchema(openapi3.NewFloat64Schema().WithExclusiveMin(true).WithMin(0)),
+			},
+			response: SampleRateStats{}, errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{method: http.MethodGet, path: "/api/stats/albums-per-decade", id: "getAlbumsPerDecade",
+			summary:  "Returns the number of albums released per decade.",
+			response: []*DecadeStats{}, errors: []int{http.StatusInternalServerError}},
+	}
+}
+
+// newOpenAPIDocument generates the OpenAPI 3 document of all routes. The schemas of the request and response bodies
+// are generated from the Go types that the handlers encode and decode.
+func newOpenAPIDocument(cfg *Config) (*openapi3.T, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:   appName,
+			Version: "1.0.0",
+			Description: "Music catalog stored in Cloud Spanner and accessed through PGAdapter. " +
+				"The same operations are also available over gRPC and GraphQL.",
+		},
+		Paths: openapi3.Paths{},
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"apiKey": &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().
+					WithType("apiKey").WithIn("header").WithName(apiKeyHeader)},
+				"bearer": &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+			},
+		},
+	}
+	// The protected routes accept either an API key or a JWT, if authentication is enabled.
+	security := openapi3.NewSecurityRequirements().
+		With(openapi3.NewSecurityRequirement().Authenticate("apiKey")).
+		With(openapi3.NewSecurityRequirement().Authenticate("bearer"))
+	gen := &openAPISchemaGenerator{schemas: doc.Components.Schemas}
+	errorSchema := gen.ref(reflect.TypeOf(ErrorResponse{}))
+
+	for _, op := range openAPIOperations() {
+		operation := openapi3.NewOperation()
+		operation.OperationID = op.id
+		operation.Summary = op.summary
+		for _, param := range op.params {
+			operation.AddParameter(param)
+		}
+		if op.request != nil {
+			operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).
+				WithJSONSchemaRef(gen.ref(reflect.TypeOf(op.request)))}
+		}
+		contentType := op.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		responseSchema := gen.ref(reflect.TypeOf(op.response))
+		for _, code := range append([]int{http.StatusOK}, op.statuses...) {
+			operation.AddResponse(code, openapi3.NewResponse().WithDescription(http.StatusText(code)).
+				WithContent(openapi3.NewContentWithSchemaRef(responseSchema, []string{contentType})))
+		}
+		errorCodes := op.errors
+		if op.path == "/graphql" || strings.HasPrefix(op.path, "/api/") {
+			// These routes are behind the rate limiter, see main, and use the database of the selected tenant.
+			operation.AddParameter(openapi3.NewHeaderParameter(cfg.TenantHeader).WithSchema(openapi3.NewStringSchema()).
+				WithDescription("The tenant whose database is used. Requests without a tenant use the default database."))
+			errorCodes = append(errorCodes[:len(errorCodes):len(errorCodes)],
+				http.StatusNotFound, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+		}
+		if op.method == http.MethodPost && strings.HasPrefix(op.path, "/api/") {
+			// See idempotencyStore.
+			operation.AddParameter(openapi3.NewHeaderParameter(client.IdempotencyKeyHeader).
+				WithSchema(openapi3.NewStringSchema().WithMaxLength(maxIdempotencyKeyLength)).
+				WithDescription("Makes retries of the request return the response of the first request instead of executing it again."))
+			errorCodes = append(errorCodes[:len(errorCodes):len(errorCodes)], http.StatusConflict, http.StatusUnprocessableEntity)
+		}
+		if op.path == "/graphql" || strings.HasPrefix(op.path, "/api/") || strings.HasPrefix(op.path, "/admin/") {
+			operation.Security = security
+			errorCodes = append(errorCodes[:len(errorCodes):len(errorCodes)], http.StatusUnauthorized, http.StatusForbidden)
+		}
+		for _, code := range errorCodes {
+			operation.AddResponse(code, openapi3.NewResponse().WithDescription(http.StatusText(code)).
+				WithJSONSchemaRef(errorSchema))
+		}
+		doc.AddOperation(op.path, op.method, operation)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+// openAPISchemaGenerator generates schemas that follow the encoding/json rules, including the flattening of
+// embedded structs such as BaseModel. Named struct types are added to the component schemas and referenced,
+// which also takes care of cycles such as Album -> Singer -> Albums.
+type openAPISchemaGenerator struct {
+	schemas openapi3.Schemas
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	dateType        = reflect.TypeOf(datatypes.Date{})
+	decimalType     = reflect.TypeOf(decimal.Decimal{})
+	nullDecimalType = reflect.TypeOf(decimal.NullDecimal{})
+)
+
+func (g *openAPISchemaGenerator) ref(t reflect.Type) *openapi3.SchemaRef {
+	switch t {
+	case timeType, dateType:
+		return openapi3.NewDateTimeSchema().NewRef()
+	case durationType:
+		// Durations are encoded as strings like 200ms, see FaultRule.
+		return openapi3.NewStringSchema().NewRef()
+	case decimalType:
+		return openapi3.NewStringSchema().WithPattern(`^-?[0-9]+(\.[0-9]+)?$`).NewRef()
+	case nullDecimalType:
+		return openapi3.NewStringSchema().WithPattern(`^-?[0-9]+(\.[0-9]+)?$`).WithNullable().NewRef()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		ref := g.ref(t.Elem())
+		if ref.Ref != "" {
+			// Siblings of $ref are ignored, so a nullable reference must be wrapped.
+			return openapi3.NewSchemaRef("", &openapi3.Schema{Nullable: true, AllOf: openapi3.SchemaRefs{ref}})
+		}
+		ref.Value.Nullable = true
+		return ref
+	case reflect.String:
+		return openapi3.NewStringSchema().NewRef()
+	case reflect.Bool:
+		return openapi3.NewBoolSchema().NewRef()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openapi3.NewIntegerSchema().NewRef()
+	case reflect.Float32, reflect.Float64:
+		return openapi3.NewFloat64Schema().NewRef()
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return openapi3.NewBytesSchema().WithNullable().NewRef()
+		}
+		schema := openapi3.NewArraySchema().WithNullable()
+		schema.Items = g.ref(t.Elem())
+		return schema.NewRef()
+	case reflect.Map:
+		schema := openapi3.NewObjectSchema().WithNullable()
+		schema.AdditionalProperties = openapi3.AdditionalProperties{Schema: g.ref(t.Elem())}
+		return schema.NewRef()
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return openapi3.NewSchemaRef("", g.structSchema(t))
+		}
+		if _, ok := g.schemas[name]; !ok {
+			// Register the name before generating the properties to stop recursion.
+			g.schemas[name] = openapi3.NewSchemaRef("", openapi3.NewObjectSchema())
+			g.schemas[name].Value = g.structSchema(t)
+		}
+		return openapi3.NewSchemaRef("#/components/schemas/"+name, g.schemas[name].Value)
+	}
+	// interface{} and other types accept any value.
+	return openapi3.NewSchemaRef("", &openapi3.Schema{})
+}
+
+func (g *openAPISchemaGenerator) structSchema(t reflect.Type) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	g.addProperties(schema, t)
+	return schema
+}
+
+func (g *openAPISchemaGenerator) addProperties(schema *openapi3.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addProperties(schema, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.WithPropertyRef(name, g.ref(field.Type))
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func init() {
+	// The calendar exports are validated as plain strings.
+	openapi3filter.RegisterBodyDecoder("text/calendar", openapi3filter.FileBodyDecoder)
+	// Keep validation errors short, as they are returned to the client.
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+func openAPIHandler(doc *openapi3.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, doc)
+	}
+}
+
+// openAPIValidator returns a middleware that validates each request against the OpenAPI document. If
+// validateResponses is set, the responses are also validated, and a response that does not conform to the document
+// is replaced by a 500 error. Response validation buffers the full response and is intended for test environments.
+// Requests for routes that are not in the document are passed through unchecked.
+func openAPIValidator(doc *openapi3.T, validateResponses bool) (func(http.Handler) http.Handler, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+				errorRender(w, r, http.StatusBadRequest, err)
+				return
+			}
+			if !validateResponses {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if err := validateResponse(r.Context(), requestInput, rec, options); err != nil {
+				errorRender(w, r, http.StatusInternalServerError, fmt.Errorf("response does not conform to the OpenAPI document: %w", err))
+				return
+			}
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+		})
+	}, nil
+}
+
+func validateResponse(ctx context.Context, requestInput *openapi3filter.RequestValidationInput, rec *responseRecorder, options *openapi3filter.Options) error {
+	return openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 rec.status,
+		Header:                 rec.header,
+		Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+		Options:                options,
+	})
+}
+
+// responseRecorder buffers a response so that it can be validated before it is sent to the client.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
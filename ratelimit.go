@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/client"
+)
+
+// apiKeyHeader carries the API key of a client.
+const apiKeyHeader = client.APIKeyHeader
+
+// rateLimiterIdleTimeout is the time after which the limiters of a client that sent no requests are removed.
+const rateLimiterIdleTimeout = 10 * time.Minute
+
+var rateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_requests_rejected_total",
+	Help: "Number of HTTP and gRPC requests that were rejected by the rate limiter (rate) or the concurrency limit (in_flight or tenant_in_flight).",
+}, []string{"reason", "kind"})
+
+// clientLimiters are the token buckets of one client.
+type clientLimiters struct {
+	read     *rate.Limiter
+	write    *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter limits the rate of requests per client with separate token buckets for reads and writes, as writes
+// are much more expensive in Cloud Spanner, and limits the number of requests that are executed concurrently.
+type rateLimiter struct {
+	cfg      *Config
+	mu       sync.Mutex
+	clients  map[string]*clientLimiters
+	lastGC   time.Time
+	inFlight chan struct{}
+}
+
+func newRateLimiter(cfg *Config) *rateLimiter {
+	l := &rateLimiter{cfg: cfg, clients: map[string]*clientLimiters{}, lastGC: time.Now()}
+	if cfg.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, cfg.MaxInFlight)
+	}
+	return l
+}
+
+// isWrite returns true if r can modify data. GraphQL queries sent with POST are counted as writes, as the operation
+// type is only known once the request has been parsed. GraphQL mutations sent with GET are rejected by the handler.
+func isWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// requestKind returns the kind of request for the metrics of rejected requests.
+func requestKind(r *http.Request) string {
+	if isWrite(r) {
+		return "write"
+	}
+	return "read"
+}
+
+// clientKey returns the authenticated caller of the request, or the client IP otherwise. API keys are only used
+// once authentication has validated them, so that clients cannot get new buckets by sending random keys.
+func (l *rateLimiter) clientKey(r *http.Request) string {
+	if id := identityFrom(r.Context()); id != nil && id != anonymous {
+		return "user:" + id.Subject
+	}
+	if l.cfg.TrustForwardedFor {
+		// The proxy in front of the server appends the address of the client, so the last address is the only
+		// one that the client cannot forge.
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			addrs := strings.Split(forwarded, ",")
+			return "ip:" + strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// limiter returns the token bucket for the kind of request of the client.
+func (l *rateLimiter) limiter(key string, write bool, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastGC) > rateLimiterIdleTimeout {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > rateLimiterIdleTimeout {
+				delete(l.clients, k)
+			}
+		}
+		l.lastGC = now
+	}
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientLimiters{
+			read:  rate.NewLimiter(rate.Limit(l.cfg.ReadRateLimit), l.cfg.ReadBurst),
+			write: rate.NewLimiter(rate.Limit(l.cfg.WriteRateLimit), l.cfg.WriteBurst),
+		}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	if write {
+		return c.write
+	}
+	return c.read
+}
+
+// allow takes a token from the bucket of the client for the kind of request. It returns the bucket, whether the
+// request is allowed, and the tokens that are left. The bucket is nil if the kind of request is not rate limited.
+func (l *rateLimiter) allow(key string, write bool) (lim *rate.Limiter, allowed bool, tokens float64) {
+	limit := l.cfg.ReadRateLimit
+	if write {
+		limit = l.cfg.WriteRateLimit
+	}
+	if limit <= 0 {
+		return nil, true, 0
+	}
+	now := time.Now()
+	lim = l.limiter(key, write, now)
+	allowed = lim.AllowN(now, 1)
+	return lim, allowed, lim.TokensAt(now)
+}
+
+// acquire takes a slot of the concurrency limit. It returns false if all slots are taken, and otherwise a function
+// that releases the slot.
+func (l *rateLimiter) acquire() (release func(), ok bool) {
+	if l.inFlight == nil {
+		return func() {}, true
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, true
+	default:
+		return nil, false
+	}
+}
+
+// middleware rejects requests that exceed the rate limit of the client with 429, and requests that exceed the
+// concurrency limit with 503. Both responses have a Retry-After header. All responses carry the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers of the bucket that the request used.
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write, kind := isWrite(r), requestKind(r)
+
+		if lim, allowed, tokens := l.allow(l.clientKey(r), write); lim != nil {
+			limit := float64(lim.Limit())
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(lim.Burst()))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil((float64(lim.Burst())-tokens)/limit))))
+			if !allowed {
+				rateLimitedRequests.WithLabelValues("rate", kind).Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil((1-tokens)/limit))))
+				errorRender(w, r, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+				return
+			}
+		}
+
+		release, ok := l.acquire()
+		if !ok {
+			rateLimitedRequests.WithLabelValues("in_flight", kind).Inc()
+			w.Header().Set("Retry-After", "1")
+			errorRender(w, r, http.StatusServiceUnavailable, errors.New("too many concurrent requests"))
+			return
+		}
+		defer release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unaryInterceptor applies the rate and concurrency limits of the HTTP routes to gRPC calls. Calls of methods that
+// require the editor role are counted as writes. It must run after the authentication interceptor, so that
+// authenticated callers are identified by their subject rather than their address.
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	write, kind := grpcMethodRoles[info.FullMethod] == roleEditor, "read"
+	if write {
+		kind = "write"
+	}
+
+	key := "ip:unknown"
+	if id := identityFrom(ctx); id != nil && id != anonymous {
+		key = "user:" + id.Subject
+	} else if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		key = "ip:" + host
+	}
+	if lim, allowed, _ := l.allow(key, write); lim != nil && !allowed {
+		rateLimitedRequests.WithLabelValues("rate", kind).Inc()
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	release, ok := l.acquire()
+	if !ok {
+		rateLimitedRequests.WithLabelValues("in_flight", kind).Inc()
+		return nil, status.Error(codes.Unavailable, "too many concurrent requests")
+	}
+	defer release()
+	return handler(ctx, req)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiterIdentifiesUnauthenticatedClientsByAddress(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.ReadRateLimit, cfg.ReadBurst = 1, 2
+	l := newRateLimiter(cfg)
+	handler := l.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// Neither random API keys nor forged X-Forwarded-For headers give a client new buckets.
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/api/concerts", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		r.Header.Set(apiKeyHeader, fmt.Sprintf("key%d", i))
+		r.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		statuses = append(statuses, w.Code)
+	}
+	if statuses[1] != http.StatusOK || statuses[2] != http.StatusTooManyRequests {
+		t.Errorf("got status codes %v, want the third request to be rate limited", statuses)
+	}
+	if len(l.clients) != 1 {
+		t.Errorf("got %d clients, want 1", len(l.clients))
+	}
+
+	// Authenticated callers have their own buckets.
+	r := httptest.NewRequest(http.MethodGet, "/api/concerts", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	r = r.WithContext(withIdentity(r.Context(), &Identity{Subject: "ci", Role: roleReader, Method: "api_key"}))
+	if key := l.clientKey(r); key != "user:ci" {
+		t.Errorf("got key %q for an authenticated caller, want user:ci", key)
+	}
+}
+
+func TestRateLimiterUnaryInterceptor(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.WriteRateLimit, cfg.WriteBurst = 1, 1
+	cfg.MaxInFlight = 1
+	l := newRateLimiter(cfg)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1234}})
+	call := func(method string, handler grpc.UnaryHandler) codes.Code {
+		_, err := l.unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return status.Code(err)
+	}
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+
+	register := "/music.v1.MusicService/RegisterSingerWithAlbum"
+	if got := call(register, ok); got != codes.OK {
+		t.Errorf("got %v for the first write, want OK", got)
+	}
+	if got := call(register, ok); got != codes.ResourceExhausted {
+		t.Errorf("got %v for the second write, want ResourceExhausted", got)
+	}
+
+	// Reads have their own bucket, but share the concurrency limit.
+	listConcerts := "/music.v1.MusicService/ListConcerts"
+	got := call(listConcerts, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if got := call(listConcerts, ok); got != codes.Unavailable {
+			t.Errorf("got %v for a concurrent read, want Unavailable", got)
+		}
+		return nil, nil
+	})
+	if got != codes.OK {
+		t.Errorf("got %v for the first read, want OK", got)
+	}
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httplog"
+)
+
+const replayUsage = `usage: %s replay [flags] requests.jsonl`
+
+// errReplayMismatch is returned by the replay command if a response differs from the recorded response.
+var errReplayMismatch = errors.New("replayed responses differ from the recorded responses")
+
+// ReplayConfig configures the replay command, which sends recorded requests to a server and compares the responses
+// with the recorded responses.
+type ReplayConfig struct {
+	// Target is the base URL of the server that the requests are sent to.
+	Target string `yaml:"target" env:"REPLAY_TARGET" flag:"replay-target" usage:"Base URL of the HTTP API that the recorded requests are sent to"`
+	// APIKey is sent in the X-API-Key header, as the recorder does not record credentials.
+	APIKey string `yaml:"api_key" env:"REPLAY_API_KEY" flag:"replay-api-key" secret:"true" usage:"API key for the HTTP API"`
+	// Speed is the factor by which the recorded intervals between requests are shortened, 0 to send the requests
+	// without delay.
+	Speed       float64 `yaml:"speed" env:"REPLAY_SPEED" flag:"replay-speed" usage:"Speed relative to the recording, 0 to send requests without delay"`
+	Concurrency int     `yaml:"concurrency" env:"REPLAY_CONCURRENCY" flag:"replay-concurrency" usage:"Maximum number of concurrent replayed requests"`
+	// IgnoreFields are the keys of JSON objects in response bodies whose values are not compared, at any depth.
+	IgnoreFields []string `yaml:"ignore_fields" env:"REPLAY_IGNORE_FIELDS" flag:"replay-ignore-fields" usage:"Comma separated JSON keys whose values are not compared"`
+	// IgnoreGenerated compares UUIDs and timestamps in response bodies only by their format. The UUIDs of recorded
+	// responses are replaced by those of the replayed responses in later requests, so that requests can use the IDs
+	// that earlier requests created.
+	IgnoreGenerated bool `yaml:"ignore_generated" env:"REPLAY_IGNORE_GENERATED" flag:"replay-ignore-generated" usage:"Compare UUIDs and timestamps only by format, and map recorded to replayed IDs"`
+	// Format is the format of the report, table or json.
+	Format string `yaml:"format" env:"REPLAY_FORMAT" flag:"replay-format" usage:"Format of the replay report: table or json"`
+}
+
+// validate returns the problems of the replay configuration.
+func (c ReplayConfig) validate() []string {
+	var errs []string
+	if u, err := url.Parse(c.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Sprintf("replay.target must be an http(s) URL, got %q", c.Target))
+	}
+	if c.Speed < 0 {
+		errs = append(errs, "replay.speed must not be negative")
+	}
+	if c.Concurrency < 1 {
+		errs = append(errs, "replay.concurrency must be at least 1")
+	}
+	if c.Format != "table" && c.Format != "json" {
+		errs = append(errs, fmt.Sprintf("replay.format must be table or json, got %q", c.Format))
+	}
+	return errs
+}
+
+// maxRecordedBodySize limits the size of the request and response bodies that the recorder keeps in memory and
+// writes to the request log.
+const maxRecordedBodySize = 64 << 10
+
+// recordedRequest is a line of a request log that is written by the requestRecorder and read by the replay command.
+type recordedRequest struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// Path includes the query string.
+	Path     string           `json:"path"`
+	Headers  http.Header      `json:"headers,omitempty"`
+	Body     string           `json:"body,omitempty"`
+	Response recordedResponse `json:"response"`
+	// Truncated is true if the request or the response body was larger than maxRecordedBodySize, and only its
+	// beginning was recorded. Truncated requests are not replayed.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int    `json:"status"`
+	Body   string `json:"body,omitempty"`
+}
+
+// unrecordedHeaders are not recorded, as they contain credentials or are set by the client when the request is sent.
+var unrecordedHeaders = []string{"Authorization", apiKeyHeader, "Cookie", "Content-Length", "Connection",
+	"Accept-Encoding", "User-Agent", "X-Forwarded-For", "Traceparent", "Tracestate"}
+
+// requestRecorder appends the requests that it handles and their responses to a JSONL file that can be replayed
+// with the replay command.
+type requestRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func newRequestRecorder(path string) (*requestRecorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open request log: %w", err)
+	}
+	return &requestRecorder{file: f, encoder: json.NewEncoder(f)}, nil
+}
+
+func (rec *requestRecorder) Close() error {
+	return rec.file.Close()
+}
+
+// limitedBuffer keeps the first max bytes that are written to it, and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.max - b.Len(); len(p) > n {
+		b.Buffer.Write(p[:n])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (rec *requestRecorder) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := recordedRequest{Time: time.Now().UTC(), Method: r.Method, Path: r.URL.RequestURI(), Headers: r.Header.Clone()}
+		for _, h := range unrecordedHeaders {
+			entry.Headers.Del(h)
+		}
+		if r.Body != nil {
+			// Only the beginning of the body is read, and the handler reads the rest from the client.
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxRecordedBodySize+1))
+			if err != nil {
+				errorRender(w, r, http.StatusBadRequest, fmt.Errorf("could not read the request body: %w", err))
+				return
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+			if len(body) > maxRecordedBodySize {
+				body, entry.Truncated = body[:maxRecordedBodySize], true
+			}
+			entry.Body = string(body)
+		}
+		response := &limitedBuffer{max: maxRecordedBodySize}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(response)
+		next.ServeHTTP(ww, r)
+
+		entry.Truncated = entry.Truncated || response.truncated
+		entry.Response = recordedResponse{Status: ww.Status(), Body: response.String()}
+		if entry.Response.Status == 0 {
+			entry.Response.Status = http.StatusOK
+		}
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		if err := rec.encoder.Encode(entry); err != nil {
+			oplog := httplog.LogEntry(r.Context())
+			oplog.Error().Err(err).Msg("failed to record the request")
+		}
+	})
+}
+
+// readRecordedRequests reads a request log.
+func readRecordedRequests(r io.Reader) ([]recordedRequest, error) {
+	var requests []recordedRequest
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var req recordedRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if req.Method == "" || !strings.HasPrefix(req.Path, "/") {
+			return nil, fmt.Errorf("line %d: not a request log of record_requests, method and path are required", line)
+		}
+		requests = append(requests, req)
+	}
+	return requests, scanner.Err()
+}
+
+// replayCommand runs the replay subcommand with the arguments after "replay".
+func replayCommand(args []string, stdout io.Writer) error {
+	cfg, files, err := loadConfigWithArgs(args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		return fmt.Errorf(replayUsage, appName)
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	requests, err := readRecordedRequests(f)
+	if err != nil {
+		return fmt.Errorf("invalid request log %s: %w", files[0], err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	report := replay(ctx, cfg.Replay, &http.Client{Timeout: cfg.RequestTimeout}, requests)
+	if err := report.write(stdout, cfg.Replay.Format); err != nil {
+		return err
+	}
+	if len(report.Mismatches) > 0 {
+		return errReplayMismatch
+	}
+	return nil
+}
+
+// replayReport is the report of the replay command. Skipped is the number of truncated requests, which are not
+// replayed.
+type replayReport struct {
+	Target     string           `json:"target"`
+	Requests   int              `json:"requests"`
+	Matched    int              `json:"matched"`
+	Skipped    int              `json:"skipped"`
+	Mismatches []replayMismatch `json:"mismatches"`
+}
+
+// replayMismatch is a request whose response differs from the recorded response. Index is the position of the
+// request in the request log, starting at 1.
+type replayMismatch struct {
+	Index  int    `json:"index"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+func (r *replayReport) write(w io.Writer, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "target\t%s\n", r.Target)
+	fmt.Fprintf(tw, "requests\t%d\n", r.Requests)
+	fmt.Fprintf(tw, "matched\t%d\n", r.Matched)
+	fmt.Fprintf(tw, "skipped\t%d\n", r.Skipped)
+	fmt.Fprintf(tw, "mismatches\t%d\n", len(r.Mismatches))
+	if len(r.Mismatches) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "#\trequest\treason")
+		for _, m := range r.Mismatches {
+			fmt.Fprintf(tw, "%d\t%s %s\t%s\n", m.Index, m.Method, m.Path, m.Reason)
+		}
+	}
+	return tw.Flush()
+}
+
+// replay sends the requests to the target of cfg at the recorded pace and compares the responses with the recorded
+// responses. Truncated requests are skipped. Requests that are not sent because ctx is done are not included in the
+// report.
+func replay(ctx context.Context, cfg ReplayConfig, client *http.Client, requests []recordedRequest) *replayReport {
+	ids := &replayIDs{ids: map[string]string{}}
+	mismatches := make([]*replayMismatch, len(requests))
+	sent := make([]bool, len(requests))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if reason := replayRequest(ctx, cfg, client, ids, requests[i]); reason != "" {
+					mismatches[i] = &replayMismatch{Index: i + 1, Method: requests[i].Method, Path: requests[i].Path, Reason: reason}
+				}
+			}
+		}()
+	}
+	start := time.Now()
+	for i, req := range requests {
+		if cfg.Speed > 0 {
+			offset := time.Duration(float64(req.Time.Sub(requests[0].Time)) / cfg.Speed)
+			if !sleepContext(ctx, time.Until(start.Add(offset))) {
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		sent[i] = true
+		if req.Truncated {
+			continue
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	report := &replayReport{Target: cfg.Target, Mismatches: []replayMismatch{}}
+	for i := range requests {
+		if !sent[i] {
+			continue
+		}
+		report.Requests++
+		if requests[i].Truncated {
+			report.Skipped++
+		} else if mismatches[i] != nil {
+			report.Mismatches = append(report.Mismatches, *mismatches[i])
+		} else {
+			report.Matched++
+		}
+	}
+	return report
+}
+
+// replayRequest sends req and returns why the response differs from the recorded response, or "" if it matches.
+func replayRequest(ctx context.Context, cfg ReplayConfig, client *http.Client, ids *replayIDs, req recordedRequest) string {
+	path, body := req.Path, req.Body
+	if cfg.IgnoreGenerated {
+		path, body = ids.substitute(path), ids.substitute(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, strings.TrimSuffix(cfg.Target, "/")+path, strings.NewReader(body))
+	if err != nil {
+		return err.Error()
+	}
+	for name, values := range req.Headers {
+		httpReq.Header[name] = values
+	}
+	if cfg.APIKey != "" {
+		httpReq.Header.Set(apiKeyHeader, cfg.APIKey)
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err.Error()
+	}
+	defer resp.Body.Close()
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err.Error()
+	}
+	if resp.StatusCode != req.Response.Status {
+		return fmt.Sprintf("got status %d, want %d", resp.StatusCode, req.Response.Status)
+	}
+	c := &responseComparer{cfg: cfg, ids: ids}
+	return c.compareBodies(req.Response.Body, string(got))
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	// timestampPattern matches RFC 3339 timestamps and the UTC timestamps of iCalendar.
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})|\d{8}T\d{6}Z`)
+)
+
+// replayIDs maps the IDs of recorded responses to the IDs of the replayed responses.
+type replayIDs struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// match returns false if the recorded ID was already mapped to another ID.
+func (m *replayIDs) match(recorded, replayed string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id, ok := m.ids[recorded]; ok {
+		return id == replayed
+	}
+	m.ids[recorded] = replayed
+	return true
+}
+
+// substitute replaces the recorded IDs in s with the replayed IDs.
+func (m *replayIDs) substitute(s string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return uuidPattern.ReplaceAllStringFunc(s, func(id string) string {
+		if replayed, ok := m.ids[id]; ok {
+			return replayed
+		}
+		return id
+	})
+}
+
+// responseComparer compares the body of a replayed response with the recorded body.
+type responseComparer struct {
+	cfg ReplayConfig
+	ids *replayIDs
+}
+
+func (c *responseComparer) compareBodies(want, got string) string {
+	var wantJSON, gotJSON interface{}
+	if json.Unmarshal([]byte(want), &wantJSON) == nil && json.Unmarshal([]byte(got), &gotJSON) == nil {
+		return c.compareJSON("$", wantJSON, gotJSON)
+	}
+	if c.cfg.IgnoreGenerated {
+		want, got = c.normalize(want), c.normalize(got)
+	}
+	if want != got {
+		return "got a different body"
+	}
+	return ""
+}
+
+// normalize replaces the generated values of a body that is not JSON, such as a calendar, with placeholders.
+func (c *responseComparer) normalize(body string) string {
+	body = uuidPattern.ReplaceAllString(body, "<uuid>")
+	return timestampPattern.ReplaceAllString(body, "<timestamp>")
+}
+
+func (c *responseComparer) compareJSON(path string, want, got interface{}) string {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: got %s, want an object", path, jsonKind(got))
+		}
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		for key := range got {
+			if _, ok := want[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if containsString(c.cfg.IgnoreFields, key) {
+				continue
+			}
+			wantValue, inWant := want[key]
+			gotValue, inGot := got[key]
+			if inWant != inGot {
+				return fmt.Sprintf("%s.%s: got present %v, want present %v", path, key, inGot, inWant)
+			}
+			if reason := c.compareJSON(path+"."+key, wantValue, gotValue); reason != "" {
+				return reason
+			}
+		}
+		return ""
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok {
+			return fmt.Sprintf("%s: got %s, want an array", path, jsonKind(got))
+		}
+		if len(got) != len(want) {
+			return fmt.Sprintf("%s: got %d elements, want %d", path, len(got), len(want))
+		}
+		for i := range want {
+			if reason := c.compareJSON(fmt.Sprintf("%s[%d]", path, i), want[i], got[i]); reason != "" {
+				return reason
+			}
+		}
+		return ""
+	case string:
+		if got, ok := got.(string); ok && c.cfg.IgnoreGenerated {
+			if isFullMatch(uuidPattern, want) && isFullMatch(uuidPattern, got) {
+				if !c.ids.match(want, got) {
+					return fmt.Sprintf("%s: got ID %s, but %s was mapped to another ID before", path, got, want)
+				}
+				return ""
+			}
+			if isFullMatch(timestampPattern, want) && isFullMatch(timestampPattern, got) {
+				return ""
+			}
+		}
+	}
+	if !reflect.DeepEqual(want, got) {
+		return fmt.Sprintf("%s: got %v, want %v", path, got, want)
+	}
+	return ""
+}
+
+func isFullMatch(pattern *regexp.Regexp, s string) bool {
+	loc := pattern.FindStringIndex(s)
+	return loc != nil && loc[0] == 0 && loc[1] == len(s)
+}
+
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
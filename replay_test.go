@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newCatalogServer starts a server with the routes of the catalog on an empty in-memory repository. If recorder is
+// set, the requests are recorded.
+func newCatalogServer(t *testing.T, recorder *requestRecorder) *httptest.Server {
+	t.Helper()
+	m := MusicDbOperation{repo: newMemoryRepository(), cfg: defaultConfig()}
+	r := chi.NewRouter()
+	if recorder != nil {
+		r.Use(recorder.middleware)
+	}
+	r.Post("/api/register-singer-with-album", m.createSingerAlbum)
+	r.Get("/api/get-albums-of-singerid/{singerId}", m.getAlbumInfoWithSingerId)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	recorder, err := newRequestRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := newCatalogServer(t, recorder)
+	resp, err := http.Post(recorded.URL+"/api/register-singer-with-album", "application/json",
+		strings.NewReader(`{"first_name": "Alice", "last_name": "Smith", "album_name": "Songs"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids SingerAlbumIds
+	if err := json.NewDecoder(resp.Body).Decode(&ids); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	for _, singerId := range []string{ids.SingerId, "unknown"} {
+		resp, err := http.Get(recorded.URL + "/api/get-albums-of-singerid/" + singerId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	requests, err := readRecordedRequests(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 3 || requests[2].Response.Status != http.StatusNotFound {
+		t.Fatalf("got recorded requests %+v", requests)
+	}
+
+	// The replayed server generates other IDs, which are used in the lookup of the albums of the singer.
+	cfg := defaultConfig().Replay
+	cfg.Target, cfg.Speed = newCatalogServer(t, nil).URL, 0
+	report := replay(context.Background(), cfg, http.DefaultClient, requests)
+	if report.Requests != 3 || report.Matched != 3 {
+		t.Errorf("got report %+v, want 3 matched requests", report)
+	}
+
+	// Without the mapping of the IDs, the singer is not found.
+	cfg.Target, cfg.IgnoreGenerated = newCatalogServer(t, nil).URL, false
+	report = replay(context.Background(), cfg, http.DefaultClient, requests)
+	if len(report.Mismatches) != 2 || report.Mismatches[0].Index != 1 || report.Mismatches[1].Reason != "got status 404, want 200" {
+		t.Errorf("got mismatches %+v", report.Mismatches)
+	}
+	var out bytes.Buffer
+	if err := report.write(&out, "table"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "got status 404, want 200") {
+		t.Errorf("report does not contain the mismatch:\n%s", out.String())
+	}
+}
+
+func TestRecorderTruncatesLargeBodies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	recorder, err := newRequestRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received int
+	handler := recorder.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = len(body)
+		w.Write(bytes.Repeat([]byte("x"), maxRecordedBodySize+1))
+	}))
+	for _, size := range []int{10, maxRecordedBodySize + 1} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/register-singer-with-album", strings.NewReader(strings.Repeat(" ", size))))
+		if received != size || w.Body.Len() != maxRecordedBodySize+1 {
+			t.Errorf("the handler got %d of %d bytes and sent %d bytes", received, size, w.Body.Len())
+		}
+	}
+	recorder.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	requests, err := readRecordedRequests(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, req := range requests {
+		if len(req.Body) > maxRecordedBodySize || len(req.Response.Body) != maxRecordedBodySize || !req.Truncated {
+			t.Errorf("request %d: got %d and %d bytes, truncated %v", i, len(req.Body), len(req.Response.Body), req.Truncated)
+		}
+	}
+
+	// Truncated requests are not sent.
+	cfg := defaultConfig().Replay
+	cfg.Target, cfg.Speed = "http://127.0.0.1:1", 0
+	report := replay(context.Background(), cfg, http.DefaultClient, requests)
+	if report.Requests != 2 || report.Skipped != 2 || len(report.Mismatches) != 0 {
+		t.Errorf("got report %+v, want 2 skipped requests", report)
+	}
+}
+
+func TestReadRecordedRequestsRejectsOtherFiles(t *testing.T) {
+	line := `{"request_id": "user-049", "title": "Record requests", "body": "..."}`
+	if _, err := readRecordedRequests(strings.NewReader(line)); err == nil || !strings.Contains(err.Error(), "record_requests") {
+		t.Errorf("got error %v for a line of the change requests", err)
+	}
+}
+
+func TestCompareJSON(t *testing.T) {
+	c := &responseComparer{cfg: defaultConfig().Replay, ids: &replayIDs{ids: map[string]string{}}}
+	for _, tc := range []struct {
+		want, got string
+		match     bool
+	}{
+		{`{"a": 1, "MarketingBudget": "10"}`, `{"a": 1, "MarketingBudget": "20"}`, true},
+		{`{"a": 1}`, `{"a": 2}`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`[{"CreatedAt": "2024-01-01T10:00:00Z"}]`, `[{"CreatedAt": "2025-02-03T10:00:00.123+01:00"}]`, true},
+		{`["00000000-0000-4000-8000-000000000001"]`, `["00000000-0000-4000-8000-000000000002"]`, true},
+		// The ID was mapped to ...02 by the previous case.
+		{`["00000000-0000-4000-8000-000000000001"]`, `["00000000-0000-4000-8000-000000000003"]`, false},
+		{"UID:00000000-0000-4000-8000-000000000001\nDTSTAMP:20240101T100000Z", "UID:00000000-0000-4000-8000-000000000009\nDTSTAMP:20250101T100000Z", true},
+	} {
+		if reason := c.compareBodies(tc.want, tc.got); (reason == "") != tc.match {
+			t.Errorf("compare %s with %s: got %q, want match %v", tc.got, tc.want, reason, tc.match)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Page selects a part of a list. Zero values are not used.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// MusicRepository is the data access layer of the HTTP, GraphQL and gRPC handlers. Operations that do not find the
+// requested record return gorm.ErrRecordNotFound, and operations that violate a constraint of the data model return
+// a *pgconn.PgError with the SQLSTATE of the violation, so that handlers behave the same for all implementations.
+//
+// Updates are given as a map from column name to value and return the updated record, including the values that
+// are generated by the database. Associations of records that are created are not saved.
+type MusicRepository interface {
+	// Transaction executes fn in a read/write transaction. All operations of the repository that is passed to fn
+	// are executed in the transaction, which is committed if fn returns nil. fn can be called more than once if the
+	// transaction is aborted, see runTransaction.
+	Transaction(ctx context.Context, fn func(tx MusicRepository) error) error
+
+	CreateSinger(ctx context.Context, singer *Singer) error
+	UpdateSinger(ctx context.Context, id string, updates map[string]interface{}) (*Singer, error)
+	// DeleteSinger fails if the singer still has albums or concerts.
+	DeleteSinger(ctx context.Context, id string) error
+	GetSinger(ctx context.Context, id string) (*Singer, error)
+	// ListSingers returns singers ordered by last name.
+	ListSingers(ctx context.Context, page Page) ([]*Singer, error)
+	SingersByIds(ctx context.Context, ids []string) ([]*Singer, error)
+
+	CreateAlbum(ctx context.Context, album *Album) error
+	UpdateAlbum(ctx context.Context, id string, updates map[string]interface{}) (*Album, error)
+	// DeleteAlbum also deletes the tracks of the album, as tracks are interleaved in albums with ON DELETE CASCADE.
+	DeleteAlbum(ctx context.Context, id string) error
+	// ListAlbums returns albums ordered by title. If singerId is not empty, only the albums of that singer are
+	// returned.
+	ListAlbums(ctx context.Context, singerId string, page Page) ([]*Album, error)
+	AlbumsByIds(ctx context.Context, ids []string) ([]*Album, error)
+	// AlbumsBySingerIds returns the albums of the singers. The albums of each singer are ordered by title.
+	AlbumsBySingerIds(ctx context.Context, singerIds []string) ([]*Album, error)
+	// AlbumsOfSinger returns the albums of a singer with their singer and tracks.
+	AlbumsOfSinger(ctx context.Context, singerId string) ([]*Album, error)
+
+	// CreateTracks creates tracks of albums that already exist.
+	CreateTracks(ctx context.Context, tracks []*Track) error
+	// TracksByAlbumIds returns the tracks of the albums. The tracks of each album are ordered by track number.
+	TracksByAlbumIds(ctx context.Context, albumIds []string) ([]*Track, error)
+
+	CreateVenue(ctx context.Context, venue *Venue) error
+	UpdateVenue(ctx context.Context, id string, updates map[string]interface{}) (*Venue, error)
+	// DeleteVenue fails if the venue still has concerts.
+	DeleteVenue(ctx context.Context, id string) error
+	GetVenue(ctx context.Context, id string) (*Venue, error)
+	// ListVenues returns venues ordered by name.
+	ListVenues(ctx context.Context, page Page) ([]*Venue, error)
+	VenuesByIds(ctx context.Context, ids []string) ([]*Venue, error)
+
+	// CreateConcert fails if the concert does not end after it starts.
+	CreateConcert(ctx context.Context, concert *Concert) error
+	UpdateConcert(ctx context.Context, id string, updates map[string]interface{}) (*Concert, error)
+	DeleteConcert(ctx context.Context, id string) error
+	// FindConcerts returns the concerts matching the filter ordered by start time, see FindConcerts. If
+	// withAssociations is true, the venue and singer of each concert are loaded as well.
+	FindConcerts(ctx context.Context, filter ConcertFilter, withAssociations bool) ([]*Concert, error)
+	// ConcertsBySingerIds returns the concerts of the singers. The concerts of each singer are ordered by start time.
+	ConcertsBySingerIds(ctx context.Context, singerIds []string) ([]*Concert, error)
+	// ConcertsByVenueIds returns the concerts at the venues. The concerts at each venue are ordered by start time.
+	ConcertsByVenueIds(ctx context.Context, venueIds []string) ([]*Concert, error)
+
+	// SingerStats returns the album, track and marketing budget numbers of all singers ordered by full name.
+	SingerStats(ctx context.Context) ([]*SingerStats, error)
+	// SampleRateStats returns the distribution of the sample rates of all tracks in buckets of the given width.
+	SampleRateStats(ctx context.Context, bucketWidth float64) (*SampleRateStats, error)
+	// AlbumsPerDecade returns the number of albums released per decade.
+	AlbumsPerDecade(ctx context.Context) ([]*DecadeStats, error)
+}
+
+// gormRepository implements MusicRepository with GORM on Cloud Spanner through PGAdapter.
+type gormRepository struct {
+	db *gorm.DB
+	// inTransaction is true for the repository that is passed to the function of Transaction. Its db is the
+	// transaction, which already has the context of the transaction span.
+	inTransaction bool
+}
+
+func newGormRepository(db *gorm.DB) *gormRepository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) conn(ctx context.Context) *gorm.DB {
+	if r.inTransaction {
+		return r.db
+	}
+	return r.db.WithContext(ctx)
+}
+
+func (r *gormRepository) Transaction(ctx context.Context, fn func(tx MusicRepository) error) error {
+	if r.inTransaction {
+		// Cloud Spanner does not support savepoints, so nested transactions are part of the outer transaction.
+		return fn(r)
+	}
+	return runTransaction(r.conn(ctx), func(tx *gorm.DB) error {
+		return fn(&gormRepository{db: tx, inTransaction: true})
+	})
+}
+
+// create inserts record without its associations.
+func (r *gormRepository) create(ctx context.Context, record interface{}) error {
+	return r.conn(ctx).Omit(clause.Associations).Create(record).Error
+}
+
+// update updates the columns of the record of type T with the given id, and then reloads the record so that values
+// that are generated by the database are also returned.
+func update[T any](ctx context.Context, r *gormRepository, id string, updates map[string]interface{}) (*T, error) {
+	record := new(T)
+	if err := r.Transaction(ctx, func(tx MusicRepository) error {
+		db := tx.(*gormRepository).db
+		if len(updates) > 0 {
+			res := db.Model(record).Where("id = ?", id).Updates(updates)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return db.Where("id = ?", id).Take(record).Error
+	}); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// remove deletes the record of type T with the given id.
+func remove[T any](ctx context.Context, r *gormRepository, id string) error {
+	res := r.conn(ctx).Where("id = ?", id).Delete(new(T))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// get returns the record of type T with the given id.
+func get[T any](ctx context.Context, r *gormRepository, id string) (*T, error) {
+	record := new(T)
+	if err := r.conn(ctx).Where("id = ?", id).Take(record).Error; err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// find returns the records of type T for which column is one of values. GORM sends a parameter for each value of an
+// IN list, so the values are queried in chunks of maxStatementParams. The records of each chunk are in the given
+// order, so the records with the same value of column are too.
+func find[T any](ctx context.Context, r *gormRepository, column string, values []string, order string) ([]*T, error) {
+	records := []*T{}
+	for start := 0; start < len(values); start += maxStatementParams {
+		end := start + maxStatementParams
+		if end > len(values) {
+			end = len(values)
+		}
+		var chunk []*T
+		if err := r.conn(ctx).Where(column+" IN ?", values[start:end]).Order(order).Find(&chunk).Error; err != nil {
+			return nil, err
+		}
+		records = append(records, chunk...)
+	}
+	return records, nil
+}
+
+// uniqueIds returns the distinct non-empty ids of records in the order of their first occurrence.
+func uniqueIds[T any](records []*T, id func(*T) string) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, record := range records {
+		if v := id(record); v != "" && !seen[v] {
+			seen[v] = true
+			ids = append(ids, v)
+		}
+	}
+	return ids
+}
+
+func paginate(db *gorm.DB, page Page) *gorm.DB {
+	if page.Limit > 0 {
+		db = db.Limit(page.Limit)
+	}
+	if page.Offset > 0 {
+		db = db.Offset(page.Offset)
+	}
+	return db
+}
+
+// CreateSinger creates the singer and reloads it, as Cloud Spanner does not return the generated full name with the
+// insert statement.
+func (r *gormRepository) CreateSinger(ctx context.Context, singer *Singer) error {
+	if err := r.create(ctx, singer); err != nil {
+		return err
+	}
+	return r.conn(ctx).Take(singer).Error
+}
+
+func (r *gormRepository) UpdateSinger(ctx context.Context, id string, updates map[string]interface{}) (*Singer, error) {
+	return update[Singer](ctx, r, id, updates)
+}
+
+func (r *gormRepository) DeleteSinger(ctx context.Context, id string) error {
+	return remove[Singer](ctx, r, id)
+}
+
+func (r *gormRepository) GetSinger(ctx context.Context, id string) (*Singer, error) {
+	return get[Singer](ctx, r, id)
+}
+
+func (r *gormRepository) ListSingers(ctx context.Context, page Page) ([]*Singer, error) {
+	singers := []*Singer{}
+	if err := paginate(r.conn(ctx), page).Order("last_name, id").Find(&singers).Error; err != nil {
+		return nil, err
+	}
+	return singers, nil
+}
+
+func (r *gormRepository) SingersByIds(ctx context.Context, ids []string) ([]*Singer, error) {
+	return find[Singer](ctx, r, "id", ids, "id")
+}
+
+func (r *gormRepository) CreateAlbum(ctx context.Context, album *Album) error {
+	return r.create(ctx, album)
+}
+
+func (r *gormRepository) UpdateAlbum(ctx context.Context, id string, updates map[string]interface{}) (*Album, error) {
+	return update[Album](ctx, r, id, updates)
+}
+
+func (r *gormRepository) DeleteAlbum(ctx context.Context, id string) error {
+	return remove[Album](ctx, r, id)
+}
+
+func (r *gormRepository) ListAlbums(ctx context.Context, singerId string, page Page) ([]*Album, error) {
+	query := paginate(r.conn(ctx), page)
+	if singerId != "" {
+		query = query.Where("singer_id = ?", singerId)
+	}
+	albums := []*Album{}
+	if err := query.Order("title, id").Find(&albums).Error; err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+func (r *gormRepository) AlbumsByIds(ctx context.Context, ids []string) ([]*Album, error) {
+	return find[Album](ctx, r, "id", ids, "id")
+}
+
+func (r *gormRepository) AlbumsBySingerIds(ctx context.Context, singerIds []string) ([]*Album, error) {
+	return find[Album](ctx, r, "singer_id", singerIds, "title, id")
+}
+
+// AlbumsOfSinger loads the tracks with find instead of Preload, as Preload sends the IDs of all albums in one IN list.
+func (r *gormRepository) AlbumsOfSinger(ctx context.Context, singerId string) ([]*Album, error) {
+	albums := []*Album{}
+	if err := r.conn(ctx).Where("singer_id = ?", singerId).Find(&albums).Error; err != nil {
+		return nil, err
+	}
+	if len(albums) == 0 {
+		return albums, nil
+	}
+	singer, err := get[Singer](ctx, r, singerId)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := r.TracksByAlbumIds(ctx, uniqueIds(albums, func(a *Album) string { return a.ID }))
+	if err != nil {
+		return nil, err
+	}
+	tracksByAlbum := map[string][]Track{}
+	for _, track := range tracks {
+		tracksByAlbum[track.ID] = append(tracksByAlbum[track.ID], *track)
+	}
+	for _, album := range albums {
+		album.Singer, album.Tracks = *singer, tracksByAlbum[album.ID]
+	}
+	return albums, nil
+}
+
+// CreateTracks creates the tracks in batches of 8, as PGAdapter can handle at most 50 parameters in a prepared
+// statement, see CreateAlbumWithRandomTracks.
+func (r *gormRepository) CreateTracks(ctx context.Context, tracks []*Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+	return r.conn(ctx).Omit(clause.Associations).CreateInBatches(tracks, 8).Error
+}
+
+func (r *gormRepository) TracksByAlbumIds(ctx context.Context, albumIds []string) ([]*Track, error) {
+	return find[Track](ctx, r, "id", albumIds, "id, track_number")
+}
+
+func (r *gormRepository) CreateVenue(ctx context.Context, venue *Venue) error {
+	return r.create(ctx, venue)
+}
+
+func (r *gormRepository) UpdateVenue(ctx context.Context, id string, updates map[string]interface{}) (*Venue, error) {
+	return update[Venue](ctx, r, id, updates)
+}
+
+func (r *gormRepository) DeleteVenue(ctx context.Context, id string) error {
+	return remove[Venue](ctx, r, id)
+}
+
+func (r *gormRepository) GetVenue(ctx context.Context, id string) (*Venue, error) {
+	return get[Venue](ctx, r, id)
+}
+
+func (r *gormRepository) ListVenues(ctx context.Context, page Page) ([]*Venue, error) {
+	venues := []*Venue{}
+	if err := paginate(r.conn(ctx), page).Order("name, id").Find(&venues).Error; err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+func (r *gormRepository) VenuesByIds(ctx context.Context, ids []string) ([]*Venue, error) {
+	return find[Venue](ctx, r, "id", ids, "id")
+}
+
+func (r *gormRepository) CreateConcert(ctx context.Context, concert *Concert) error {
+	return r.create(ctx, concert)
+}
+
+func (r *gormRepository) UpdateConcert(ctx context.Context, id string, updates map[string]interface{}) (*Concert, error) {
+	return update[Concert](ctx, r, id, updates)
+}
+
+func (r *gormRepository) DeleteConcert(ctx context.Context, id string) error {
+	return remove[Concert](ctx, r, id)
+}
+
+// FindConcerts loads the venues and singers with find instead of Preload, as a page of concerts can have more
+// venues and singers than PGAdapter accepts parameters.
+func (r *gormRepository) FindConcerts(ctx context.Context, filter ConcertFilter, withAssociations bool) ([]*Concert, error) {
+	concerts, err := FindConcerts(r.conn(ctx), filter)
+	if err != nil || !withAssociations || len(concerts) == 0 {
+		return concerts, err
+	}
+	venues, err := r.VenuesByIds(ctx, uniqueIds(concerts, func(c *Concert) string { return c.VenueId }))
+	if err != nil {
+		return nil, err
+	}
+	singers, err := r.SingersByIds(ctx, uniqueIds(concerts, func(c *Concert) string { return c.SingerId }))
+	if err != nil {
+		return nil, err
+	}
+	venuesById, singersById := map[string]*Venue{}, map[string]*Singer{}
+	for _, venue := range venues {
+		venuesById[venue.ID] = venue
+	}
+	for _, singer := range singers {
+		singersById[singer.ID] = singer
+	}
+	for _, concert := range concerts {
+		if venue, ok := venuesById[concert.VenueId]; ok {
+			concert.Venue = *venue
+		}
+		if singer, ok := singersById[concert.SingerId]; ok {
+			concert.Singer = *singer
+		}
+	}
+	return concerts, nil
+}
+
+func (r *gormRepository) ConcertsBySingerIds(ctx context.Context, singerIds []string) ([]*Concert, error) {
+	return find[Concert](ctx, r, "singer_id", singerIds, "start_time, id")
+}
+
+func (r *gormRepository) ConcertsByVenueIds(ctx context.Context, venueIds []string) ([]*Concert, error) {
+	return find[Concert](ctx, r, "venue_id", venueIds, "start_time, id")
+}
+
+var (
+	_ MusicRepository = (*gormRepository)(nil)
+	_ MusicRepository = (*memoryRepository)(nil)
+)
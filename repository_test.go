@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+func TestGormRepositoryStaysBelowTheParameterLimit(t *testing.T) {
+	server, db := newFakeDb(t)
+	server.MaxParams = maxStatementParams
+	repo := newGormRepository(db)
+	ctx := context.Background()
+
+	ids := make([]string, 120)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("s%d", i)
+	}
+	if _, err := repo.SingersByIds(ctx, ids); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each concert of the page has another venue and singer.
+	columns := []pgfake.Column{{Name: "id", OID: pgtype.TextOID}, {Name: "venue_id", OID: pgtype.TextOID},
+		{Name: "singer_id", OID: pgtype.TextOID}}
+	var rows [][]interface{}
+	for i := 0; i < 60; i++ {
+		rows = append(rows, []interface{}{fmt.Sprintf("c%d", i), fmt.Sprintf("v%d", i), fmt.Sprintf("s%d", i)})
+	}
+	server.On(`FROM "concerts"`, pgfake.Rows(columns, rows...))
+	if _, err := repo.FindConcerts(ctx, ConcertFilter{Limit: 60}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		fragment   string
+		statements int
+	}{
+		// 120 singer IDs, then the 60 singers of the concerts.
+		{`FROM "singers"`, 5},
+		{`FROM "venues"`, 2},
+	} {
+		statements := statementsWith(server, tc.fragment)
+		if len(statements) != tc.statements {
+			t.Errorf("%s: got %d statements, want %d", tc.fragment, len(statements), tc.statements)
+		}
+		for _, stmt := range statements {
+			if len(stmt.Params) > maxStatementParams {
+				t.Errorf("got %d parameters, want at most %d: %s", len(stmt.Params), maxStatementParams, stmt.SQL)
+			}
+		}
+	}
+}
@@ -21,87 +21,109 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"math/rand"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/shopspring/decimal"
 	"gorm.io/datatypes"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/datagen"
+	"shin5ok/simple-gorm-with-cloud-spanner/model"
 )
 
 // TODO(developer): Change this to match your PGAdapter instance and database name
 var connectionString = "host=/tmp port=5433 database=gorm-sample2"
 
-// BaseModel is embedded in all other models to add common database fields.
-type BaseModel struct {
-	// ID is the primary key of each model. The ID is generated client side as a UUID.
-	// Adding the `primaryKey` annotation is redundant for most models, as gorm will assume that the column with name ID
-	// is the primary key. This is however not redundant for models that add additional primary key columns, such as
-	// child tables in interleaved table hierarchies, as a missing primary key annotation here would then cause the
-	// primary key column defined on the child table to be the only primary key column.
-	ID string `gorm:"primaryKey;autoIncrement:false"`
-	// CreatedAt and UpdatedAt are managed automatically by gorm.
-	CreatedAt time.Time
-	UpdatedAt time.Time
-}
+// The models are defined in the model package, so that the client of the API decodes the same types that the server
+// encodes.
+type (
+	BaseModel = model.BaseModel
+	Singer    = model.Singer
+	Album     = model.Album
+	Track     = model.Track
+	Venue     = model.Venue
+	Concert   = model.Concert
+)
 
-type Singer struct {
-	BaseModel
-	FirstName sql.NullString
-	LastName  string
-	// FullName is generated by the database. The '->' marks this a read-only field. Preferably this field should also
-	// include a `default:(-)` annotation, as that would make gorm read the value back using a RETURNING clause. That is
-	// however currently not supported.
-	FullName string `gorm:"->;type:GENERATED ALWAYS AS (coalesce(concat(first_name,' '::varchar,last_name))) STORED;default:(-);"`
-	Active   bool
-	Albums   []Album
-}
+// vocabulary contains the words of the generated names and titles, see Config.DataVocabulary.
+var vocabulary = datagen.DefaultVocabulary()
 
-type Album struct {
-	BaseModel
-	Title           string
-	MarketingBudget decimal.NullDecimal
-	ReleaseDate     datatypes.Date
-	CoverPicture    []byte
-	SingerId        string
-	Singer          Singer
-	Tracks          []Track `gorm:"foreignKey:ID"`
-}
+// sampleData generates the data of the sample steps and of the initial data, which are executed sequentially.
+// Concurrent callers such as the handlers and the load test workers use their own generator, see newDataGenerator.
+var sampleData = newDataGenerator()
 
-// Track is interleaved in Album. The ID column is both the first part of the primary key of Track, and a
-// reference to the Album that owns the Track.
-type Track struct {
-	BaseModel
-	TrackNumber int64 `gorm:"primaryKey;autoIncrement:false"`
-	Title       string
-	SampleRate  float64
-	Album       Album `gorm:"foreignKey:ID"`
+// newDataGenerator returns a randomly seeded generator with the configured vocabulary.
+func newDataGenerator() *datagen.Generator {
+	return datagen.New(datagen.NewSeed(), vocabulary)
 }
 
-type Venue struct {
-	BaseModel
-	Name        string
-	Description string
+// configureDataGen loads the vocabulary of cfg, and seeds sampleData with the seed of cfg if it is set, so that the
+// sample and the initial data can be reproduced.
+func configureDataGen(cfg *Config) error {
+	if cfg.DataVocabulary != "" {
+		v, err := datagen.LoadVocabulary(cfg.DataVocabulary)
+		if err != nil {
+			return err
+		}
+		vocabulary = v
+	}
+	seed := cfg.DataSeed
+	if seed == 0 {
+		seed = datagen.NewSeed()
+	}
+	sampleData = datagen.New(seed, vocabulary)
+	return nil
 }
 
-type Concert struct {
-	BaseModel
-	Name      string
-	Venue     Venue
-	VenueId   string
-	Singer    Singer
-	SingerId  string
-	StartTime time.Time
-	EndTime   time.Time
+// sampleStep is a step of the sample. The steps of sampleSteps are executed in order, as later steps use the data that
+// is created by earlier steps.
+type sampleStep struct {
+	name        string
+	description string
+	run         func(db *gorm.DB) error
 }
 
-var rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+var sampleSteps = []sampleStep{
+	{"CreateTablesIfNotExist", "Create the sample tables if they do not yet exist", CreateTablesIfNotExist},
+	{"DeleteAllData", "Delete all existing data to start with a clean database", func(db *gorm.DB) error {
+		if err := DeleteAllData(db); err != nil {
+			return err
+		}
+		fmt.Print("Purged all existing test data\n\n")
+		return nil
+	}},
+	{"CreateRandomSingersAndAlbums", "Create some random Singers, Albums and Tracks", CreateRandomSingersAndAlbums},
+	{"PrintSingersAlbumsAndTracks", "Print the generated Singers, Albums and Tracks", PrintSingersAlbumsAndTracks},
+	{"CreateVenueAndConcertInTransaction", "Create a Concert for a random singer", CreateVenueAndConcertInTransaction},
+	{"PrintConcerts", "Print all Concerts in the database", PrintConcerts},
+	{"PrintAlbumsReleaseBefore1900", "Print all Albums that were released before 1900", PrintAlbumsReleaseBefore1900},
+	// The function executes multiple queries to fetch a batch of singers per query.
+	{"PrintSingersWithLimitAndOffset", "Print all Singers ordered by last name", PrintSingersWithLimitAndOffset},
+	{"PrintAlbumsFirstCharTitleAndFirstOrLastNameEqual", "Print all Albums that have a title where the first " +
+		"character of the title matches either the first character of the first name or first character of the last " +
+		"name of the Singer", PrintAlbumsFirstCharTitleAndFirstOrLastNameEqual},
+	{"SearchAlbumsUsingNamedArgument", "Print all Albums whose title start with 'e' using a named argument", func(db *gorm.DB) error {
+		return SearchAlbumsUsingNamedArgument(db, "e%")
+	}},
+	{"UpdateVenueDescription", "Update Venue description", UpdateVenueDescription},
+	{"FirstOrInitVenue", "Use FirstOrInit to create or update a Venue", func(db *gorm.DB) error {
+		return FirstOrInitVenue(db, "Berlin Arena")
+	}},
+	{"FirstOrCreateVenue", "Use FirstOrCreate to create a Venue if it does not already exist", func(db *gorm.DB) error {
+		return FirstOrCreateVenue(db, "Paris Central")
+	}},
+	{"UpdateTracksInBatches", "Update all Tracks by fetching them in batches and then applying an update to each " +
+		"record", UpdateTracksInBatches},
+	{"DeleteRandomTrack", "Delete a random Track from the database", DeleteRandomTrack},
+	// This will also delete any child Track records interleaved with the Album.
+	{"DeleteRandomAlbum", "Delete a random Album from the database", DeleteRandomAlbum},
+	{"QueryWithTimeout", "Try to execute a query with a 1ms timeout. This will normally fail", QueryWithTimeout},
+}
 
 func RunSample(connString string) error {
 	db, err := gorm.Open(postgres.Open(connString), &gorm.Config{
@@ -115,86 +137,11 @@ func RunSample(connString string) error {
 		fmt.Printf("Failed to open gorm connection: %v\n", err)
 	}
 
-	// Create the sample tables if they do not yet exist.
-	if err := CreateTablesIfNotExist(db); err != nil {
-		return err
-	}
-
 	fmt.Println("Starting sample...")
-
-	// Delete all existing data to start with a clean database.
-	if err := DeleteAllData(db); err != nil {
-		return err
-	}
-	fmt.Print("Purged all existing test data\n\n")
-
-	// Create some random Singers, Albums and Tracks.
-	if err := CreateRandomSingersAndAlbums(db); err != nil {
-		return err
-	}
-	// Print the generated Singers, Albums and Tracks.
-	if err := PrintSingersAlbumsAndTracks(db); err != nil {
-		return err
-	}
-
-	// Create a Concert for a random singer.
-	if err := CreateVenueAndConcertInTransaction(db); err != nil {
-		return err
-	}
-	// Print all Concerts in the database.
-	if err := PrintConcerts(db); err != nil {
-		return err
-	}
-	// Print all Albums that were released before 1900.
-	if err := PrintAlbumsReleaseBefore1900(db); err != nil {
-		return err
-	}
-	// Print all Singers ordered by last name.
-	// The function executes multiple queries to fetch a batch of singers per query.
-	if err := PrintSingersWithLimitAndOffset(db); err != nil {
-		return err
-	}
-	// Print all Albums that have a title where the first character of the title matches
-	// either the first character of the first name or first character of the last name
-	// of the Singer.
-	if err := PrintAlbumsFirstCharTitleAndFirstOrLastNameEqual(db); err != nil {
-		return err
-	}
-	// Print all Albums whose title start with 'e'. The function uses a named argument for the query.
-	if err := SearchAlbumsUsingNamedArgument(db, "e%"); err != nil {
-		return err
-	}
-
-	// Update Venue description.
-	if err := UpdateVenueDescription(db); err != nil {
-		return err
-	}
-	// Use FirstOrInit to create or update a Venue.
-	if err := FirstOrInitVenue(db, "Berlin Arena"); err != nil {
-		return err
-	}
-	// Use FirstOrCreate to create a Venue if it does not already exist.
-	if err := FirstOrCreateVenue(db, "Paris Central"); err != nil {
-		return err
-	}
-	// Update all Tracks by fetching them in batches and then applying an update to each record.
-	if err := UpdateTracksInBatches(db); err != nil {
-		return err
-	}
-
-	// Delete a random Track from the database.
-	if err := DeleteRandomTrack(db); err != nil {
-		return err
-	}
-	// Delete a random Album from the database. This will also delete any child Track records interleaved with the
-	// Album.
-	if err := DeleteRandomAlbum(db); err != nil {
-		return err
-	}
-
-	// Try to execute a query with a 1ms timeout. This will normally fail.
-	if err := QueryWithTimeout(db); err != nil {
-		return err
+	for _, step := range sampleSteps {
+		if err := step.run(db); err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Finished running sample\n")
@@ -206,16 +153,16 @@ func CreateRandomSingersAndAlbums(db *gorm.DB) error {
 	fmt.Println("Creating random singers and albums")
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		// Create between 5 and 10 random singers.
-		for i := 0; i < randInt(5, 10); i++ {
-			singerId, err := CreateSinger(db, randFirstName(), randLastName())
+		for i, n := 0, sampleData.Between(5, 10); i < n; i++ {
+			singerId, err := CreateSinger(db, sampleData.FirstName(), sampleData.LastName())
 			if err != nil {
 				fmt.Printf("Failed to create singer: %v\n", err)
 				return err
 			}
 			fmt.Print(".")
 			// Create between 2 and 12 random albums
-			for j := 0; j < randInt(2, 12); j++ {
-				_, err = CreateAlbumWithRandomTracks(db, singerId, randAlbumTitle(), randInt(1, 22))
+			for j, n := 0, sampleData.Between(2, 12); j < n; j++ {
+				_, err = CreateAlbumWithRandomTracks(db, singerId, sampleData.AlbumTitle(), sampleData.TrackCount())
 				if err != nil {
 					fmt.Printf("Failed to create album: %v\n", err)
 					return err
@@ -318,13 +265,13 @@ func PrintConcerts(db *gorm.DB) error {
 func UpdateVenueDescription(db *gorm.DB) error {
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		venue := Venue{}
-		if res := tx.Find(&venue, "name = ?", "Avenue Park"); res != nil {
+		if res := tx.Find(&venue, "name = ?", "Avenue Park"); res.Error != nil {
 			return res.Error
 		}
 		// Update the description of the Venue.
 		venue.Description = `{"Capacity": 10000, "Location": "New York", "Country": "US", "Type": "Park"}`
 
-		if res := tx.Update("description", &venue); res.Error != nil {
+		if res := tx.Model(&venue).Update("description", venue.Description); res.Error != nil {
 			return res.Error
 		}
 		// Return nil to instruct `gorm` to commit the transaction.
@@ -354,7 +301,7 @@ func FirstOrInitVenue(db *gorm.DB, name string) error {
 		if venue.ID == "" {
 			return tx.Create(&venue).Error
 		}
-		return tx.Update("description", &venue).Error
+		return tx.Model(&venue).Update("description", venue.Description).Error
 	}); err != nil {
 		fmt.Printf("Failed to create or update Venue %q: %v\n", name, err)
 		return err
@@ -525,43 +472,34 @@ func SearchAlbumsUsingNamedArgument(db *gorm.DB, title string) error {
 // CreateSinger creates a new Singer and stores in the database.
 // Returns the ID of the Singer.
 func CreateSinger(db *gorm.DB, firstName, lastName string) (string, error) {
-	singer := Singer{
-		BaseModel: BaseModel{ID: uuid.NewString()},
-		FirstName: sql.NullString{String: firstName, Valid: true},
-		LastName:  lastName,
+	singer := newSinger(firstName, lastName)
+	if err := db.Create(singer).Error; err != nil {
+		return "", err
+	}
+	// FullName is automatically generated by the database. Cloud Spanner does not return it with the insert
+	// statement, so the singer is reloaded.
+	if err := db.Take(singer).Error; err != nil {
+		return "", err
 	}
-	res := db.Create(&singer)
-	// FullName is automatically generated by the database and should be returned to the client by
-	// the insert statement.
 	if singer.FullName != firstName+" "+lastName {
 		return "", fmt.Errorf("unexpected full name for singer: %v", singer.FullName)
 	}
-	return singer.ID, res.Error
+	return singer.ID, nil
 }
 
 // CreateAlbumWithRandomTracks creates and stores a new Album in the database.
 // Also generates numTracks random tracks for the Album.
 // Returns the ID of the Album.
 func CreateAlbumWithRandomTracks(db *gorm.DB, singerId, albumTitle string, numTracks int) (string, error) {
-	albumId := uuid.NewString()
-	// We cannot include the Tracks that we want to create in the definition here, as gorm would then try to
-	// use an UPSERT to save-or-update the album that we are creating. Instead, we need to create the album first,
-	// and then create the tracks.
-	res := db.Create(&Album{
-		BaseModel:       BaseModel{ID: albumId},
-		Title:           albumTitle,
-		MarketingBudget: decimal.NullDecimal{Decimal: decimal.NewFromFloat(randFloat64(0, 10000000))},
-		ReleaseDate:     randDate(),
-		SingerId:        singerId,
-		CoverPicture:    randBytes(randInt(5000, 15000)),
-	})
+	// We cannot include the Tracks that we want to create in the album, as gorm would then try to use an UPSERT to
+	// save-or-update the album that we are creating. Instead, we need to create the album first, and then create
+	// the tracks.
+	album, tracks := newAlbumWithRandomTracks(sampleData, singerId, albumTitle, numTracks)
+	albumId := album.ID
+	res := db.Create(album)
 	if res.Error != nil {
 		return albumId, res.Error
 	}
-	tracks := make([]*Track, numTracks)
-	for n := 0; n < numTracks; n++ {
-		tracks[n] = &Track{BaseModel: BaseModel{ID: albumId}, TrackNumber: int64(n + 1), Title: randTrackTitle(), SampleRate: randFloat64(30.0, 60.0)}
-	}
 
 	// Note: The batch size is deliberately kept small here in order to prevent the statement from getting too big and
 	// exceeding the maximum number of parameters in a prepared statement. PGAdapter can currently handle at most 50
@@ -570,6 +508,33 @@ func CreateAlbumWithRandomTracks(db *gorm.DB, singerId, albumTitle string, numTr
 	return albumId, res.Error
 }
 
+// newSinger returns a new Singer with a random ID.
+func newSinger(firstName, lastName string) *Singer {
+	return &Singer{
+		BaseModel: BaseModel{ID: uuid.NewString()},
+		FirstName: sql.NullString{String: firstName, Valid: true},
+		LastName:  lastName,
+	}
+}
+
+// newAlbumWithRandomTracks returns a new Album with a random ID and values of gen, and numTracks Tracks with values of
+// gen for the Album. The Tracks are not added to the Album.
+func newAlbumWithRandomTracks(gen *datagen.Generator, singerId, albumTitle string, numTracks int) (*Album, []*Track) {
+	album := &Album{
+		BaseModel:       BaseModel{ID: uuid.NewString()},
+		Title:           albumTitle,
+		MarketingBudget: gen.MarketingBudget(),
+		ReleaseDate:     datatypes.Date(gen.ReleaseDate()),
+		SingerId:        singerId,
+		CoverPicture:    gen.CoverPicture(),
+	}
+	tracks := make([]*Track, numTracks)
+	for n := 0; n < numTracks; n++ {
+		tracks[n] = &Track{BaseModel: BaseModel{ID: album.ID}, TrackNumber: int64(n + 1), Title: gen.TrackTitle(), SampleRate: gen.SampleRate()}
+	}
+	return album, tracks
+}
+
 // DeleteRandomTrack will delete a randomly chosen Track from the database.
 // This function shows how to delete a record with a primary key consisting of more than one column.
 func DeleteRandomTrack(db *gorm.DB) error {
@@ -624,12 +589,15 @@ func DeleteRandomAlbum(db *gorm.DB) error {
 	return nil
 }
 
+// dataModelFile contains the DDL statements of the sample tables.
+var dataModelFile = "create_data_model.sql"
+
 // CreateTablesIfNotExist creates all tables that are required for this sample if tney do not yet exist.
 func CreateTablesIfNotExist(db *gorm.DB) error {
 	fmt.Println("Creating tables...")
-	ddl, err := ioutil.ReadFile("create_data_model.sql")
+	ddl, err := ioutil.ReadFile(dataModelFile)
 	if err != nil {
-		fmt.Printf("Could not read create_data_model.sql file: %v\n", err)
+		fmt.Printf("Could not read %s file: %v\n", dataModelFile, err)
 		return err
 	}
 	ddlStatements := strings.FieldsFunc(string(ddl), func(r rune) bool {
@@ -666,136 +634,6 @@ func DeleteAllData(db *gorm.DB) error {
 	return nil
 }
 
-func randFloat64(min, max float64) float64 {
-	return min + rnd.Float64()*(max-min)
-}
-
-func randInt(min, max int) int {
-	return min + rnd.Int()%(max-min)
-}
-
-func randDate() datatypes.Date {
-	return datatypes.Date(time.Date(randInt(1850, 2010), time.Month(randInt(1, 12)), randInt(1, 28), 0, 0, 0, 0, time.UTC))
-}
-
-func randBytes(length int) []byte {
-	res := make([]byte, length)
-	rnd.Read(res)
-	return res
-}
-
-func randFirstName() string {
-	return firstNames[randInt(0, len(firstNames))]
-}
-
-func randLastName() string {
-	return lastNames[randInt(0, len(lastNames))]
-}
-
-func randAlbumTitle() string {
-	return adjectives[randInt(0, len(adjectives))] + " " + nouns[randInt(0, len(nouns))]
-}
-
-func randTrackTitle() string {
-	return adverbs[randInt(0, len(adverbs))] + " " + verbs[randInt(0, len(verbs))]
-}
-
-var firstNames = []string{
-	"Saffron", "Eleanor", "Ann", "Salma", "Kiera", "Mariam", "Georgie", "Eden", "Carmen", "Darcie",
-	"Antony", "Benjamin", "Donald", "Keaton", "Jared", "Simon", "Tanya", "Julian", "Eugene", "Laurence"}
-var lastNames = []string{
-	"Terry", "Ford", "Mills", "Connolly", "Newton", "Rodgers", "Austin", "Floyd", "Doherty", "Nguyen",
-	"Chavez", "Crossley", "Silva", "George", "Baldwin", "Burns", "Russell", "Ramirez", "Hunter", "Fuller",
-}
-var adjectives = []string{
-	"ultra",
-	"happy",
-	"emotional",
-	"filthy",
-	"charming",
-	"alleged",
-	"talented",
-	"exotic",
-	"lamentable",
-	"lewd",
-	"old-fashioned",
-	"savory",
-	"delicate",
-	"willing",
-	"habitual",
-	"upset",
-	"gainful",
-	"nonchalant",
-	"kind",
-	"unruly",
-}
-var nouns = []string{
-	"improvement",
-	"control",
-	"tennis",
-	"gene",
-	"department",
-	"person",
-	"awareness",
-	"health",
-	"development",
-	"platform",
-	"garbage",
-	"suggestion",
-	"agreement",
-	"knowledge",
-	"introduction",
-	"recommendation",
-	"driver",
-	"elevator",
-	"industry",
-	"extent",
-}
-var verbs = []string{
-	"instruct",
-	"rescue",
-	"disappear",
-	"import",
-	"inhibit",
-	"accommodate",
-	"dress",
-	"describe",
-	"mind",
-	"strip",
-	"crawl",
-	"lower",
-	"influence",
-	"alter",
-	"prove",
-	"race",
-	"label",
-	"exhaust",
-	"reach",
-	"remove",
-}
-var adverbs = []string{
-	"cautiously",
-	"offensively",
-	"immediately",
-	"soon",
-	"judgementally",
-	"actually",
-	"honestly",
-	"slightly",
-	"limply",
-	"rigidly",
-	"fast",
-	"normally",
-	"unnecessarily",
-	"wildly",
-	"unimpressively",
-	"helplessly",
-	"rightfully",
-	"kiddingly",
-	"early",
-	"queasily",
-}
-
 func parseTimestamp(ts string) time.Time {
 	t, _ := time.Parse(time.RFC3339Nano, ts)
 	return t.UTC()
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+// newFakeDb starts a fake PGAdapter and opens a gorm connection to it.
+func newFakeDb(t *testing.T) (*pgfake.Server, *gorm.DB) {
+	t.Helper()
+	server, err := pgfake.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.Open(server.DSN()), &gorm.Config{
+		DisableNestedTransaction: true,
+		Logger:                   logger.Discard,
+	})
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		closeDbConn(db)
+		server.Close()
+	})
+	return server, db
+}
+
+// statementsWith returns the statements that contain fragment.
+func statementsWith(server *pgfake.Server, fragment string) []pgfake.Statement {
+	var found []pgfake.Statement
+	for _, stmt := range server.Statements() {
+		if strings.Contains(stmt.SQL, fragment) {
+			found = append(found, stmt)
+		}
+	}
+	return found
+}
+
+func TestCreateAlbumWithRandomTracks(t *testing.T) {
+	server, db := newFakeDb(t)
+
+	albumId, err := CreateAlbumWithRandomTracks(db, "singer1", "Album", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	albums := statementsWith(server, `INSERT INTO "albums"`)
+	if len(albums) != 1 {
+		t.Fatalf("album inserts: got %d, want 1", len(albums))
+	}
+	if got, want := albums[0].SQL, `INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`; got != want {
+		t.Errorf("album insert:\n got %s\nwant %s", got, want)
+	}
+	if got := albums[0].Params[0]; got != albumId {
+		t.Errorf("album id: got %v, want %v", got, albumId)
+	}
+	if got := albums[0].Params[7]; got != "singer1" {
+		t.Errorf("singer id: got %v, want singer1", got)
+	}
+
+	// The tracks must be inserted in batches of at most 8 rows to stay below the parameter limit of PGAdapter.
+	tracks := statementsWith(server, `INSERT INTO "tracks"`)
+	if len(tracks) != 2 {
+		t.Fatalf("track inserts: got %d, want 2", len(tracks))
+	}
+	for i, want := range []int{48, 12} {
+		if got := len(tracks[i].Params); got != want {
+			t.Errorf("batch %d: got %d parameters, want %d", i, got, want)
+		}
+		if tracks[i].Params[0] != albumId {
+			t.Errorf("batch %d: got album id %v, want %v", i, tracks[i].Params[0], albumId)
+		}
+	}
+}
+
+func TestCreateAlbumWithRandomTracksParameterLimit(t *testing.T) {
+	server, db := newFakeDb(t)
+	server.MaxParams = 40
+
+	_, err := CreateAlbumWithRandomTracks(db, "singer1", "Album", 10)
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgfake.TooManyParametersCode {
+		t.Fatalf("got error %v, want a parameter limit violation", err)
+	}
+	if got := statementsWith(server, `INSERT INTO "tracks"`); len(got) != 0 {
+		t.Errorf("got %d track inserts, want none", len(got))
+	}
+}
+
+func TestUpdateTracksInBatches(t *testing.T) {
+	server, db := newFakeDb(t)
+	columns := []pgfake.Column{
+		{Name: "id", OID: pgtype.TextOID},
+		{Name: "created_at", OID: pgtype.TimestamptzOID},
+		{Name: "updated_at", OID: pgtype.TimestamptzOID},
+		{Name: "track_number", OID: pgtype.Int8OID},
+		{Name: "title", OID: pgtype.TextOID},
+		{Name: "sample_rate", OID: pgtype.Float8OID},
+	}
+	now := time.Now()
+	server.On(`FROM "tracks"`, pgfake.Rows(columns,
+		[]interface{}{"album1", now, now, 1, "Track 1", 60.0},
+		[]interface{}{"album1", now, now, 2, "Track 2", 45.0},
+	))
+
+	if err := UpdateTracksInBatches(db); err != nil {
+		t.Fatal(err)
+	}
+	selects := statementsWith(server, `FROM "tracks"`)
+	if len(selects) != 1 {
+		t.Fatalf("selects: got %d, want 1", len(selects))
+	}
+	if got, want := selects[0].SQL, `SELECT * FROM "tracks" WHERE sample_rate > 44.1 ORDER BY "tracks"."id" LIMIT 20`; got != want {
+		t.Errorf("select:\n got %s\nwant %s", got, want)
+	}
+	updates := statementsWith(server, `UPDATE "tracks"`)
+	if len(updates) != 2 {
+		t.Fatalf("updates: got %d, want 2", len(updates))
+	}
+	if got, want := updates[0].SQL, `UPDATE "tracks" SET "sample_rate"=$1,"updated_at"=$2 WHERE "id" = $3 AND "track_number" = $4`; got != want {
+		t.Errorf("update:\n got %s\nwant %s", got, want)
+	}
+	for i, want := range []string{"54", "42.75"} {
+		if got := updates[i].Params[0]; got != want {
+			t.Errorf("update %d: got sample rate %v, want %v", i, got, want)
+		}
+	}
+	if got := len(statementsWith(server, "commit")); got != 1 {
+		t.Errorf("commits: got %d, want 1", got)
+	}
+}
+
+func TestUpdateTracksInBatchesAffectedRows(t *testing.T) {
+	server, db := newFakeDb(t)
+	server.On(`FROM "tracks"`, pgfake.Rows([]pgfake.Column{
+		{Name: "id", OID: pgtype.TextOID},
+		{Name: "track_number", OID: pgtype.Int8OID},
+		{Name: "sample_rate", OID: pgtype.Float8OID},
+	}, []interface{}{"album1", 1, 60.0}))
+	server.On(`UPDATE "tracks"`, pgfake.Tag("UPDATE 0"))
+
+	if err := UpdateTracksInBatches(db); err == nil || !strings.Contains(err.Error(), "affected 0 rows") {
+		t.Fatalf("got error %v, want an error for 0 affected rows", err)
+	}
+	if got := len(statementsWith(server, "rollback")); got != 1 {
+		t.Errorf("rollbacks: got %d, want 1", got)
+	}
+}
+
+func TestRunTransactionRetriesAbortedTransactions(t *testing.T) {
+	server, db := newFakeDb(t)
+	// The full name is generated by the database and read when the singer is reloaded.
+	server.On(`SELECT * FROM "singers"`, pgfake.Rows([]pgfake.Column{{Name: "full_name", OID: pgtype.TextOID}},
+		[]interface{}{"Alice Smith"}))
+	server.On("commit", pgfake.Aborted(), pgfake.Tag("COMMIT"))
+
+	attempts := 0
+	if err := runTransaction(db, func(tx *gorm.DB) error {
+		attempts++
+		_, err := CreateSinger(tx, "Alice", "Smith")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts: got %d, want 2", attempts)
+	}
+	if got := len(statementsWith(server, `INSERT INTO "singers"`)); got != 2 {
+		t.Errorf("singer inserts: got %d, want 2", got)
+	}
+}
+
+func TestRunTransactionGivesUpAfterMaxAttempts(t *testing.T) {
+	server, db := newFakeDb(t)
+	server.On("commit", pgfake.Aborted())
+
+	attempts := 0
+	err := runTransaction(db, func(tx *gorm.DB) error {
+		attempts++
+		return nil
+	})
+	if !isAbortedError(err) {
+		t.Fatalf("got error %v, want an aborted error", err)
+	}
+	if attempts != maxTransactionAttempts {
+		t.Errorf("attempts: got %d, want %d", attempts, maxTransactionAttempts)
+	}
+	if got := len(statementsWith(server, "commit")); got != maxTransactionAttempts {
+		t.Errorf("commits: got %d, want %d", got, maxTransactionAttempts)
+	}
+}
+
+func TestRunTransactionDoesNotRetryOtherErrors(t *testing.T) {
+	server, db := newFakeDb(t)
+	server.On(`INSERT INTO "venues"`, pgfake.Error("23505", "Row [v1] in table venues already exists"))
+
+	attempts := 0
+	err := runTransaction(db, func(tx *gorm.DB) error {
+		attempts++
+		return tx.Create(&Venue{BaseModel: BaseModel{ID: "v1"}, Name: "Venue"}).Error
+	})
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		t.Fatalf("got error %v, want a unique violation", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts: got %d, want 1", attempts)
+	}
+	if got := len(statementsWith(server, "rollback")); got != 1 {
+		t.Errorf("rollbacks: got %d, want 1", got)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const sampleUsage = `usage: %[1]s sample list
+       %[1]s sample run [flags] [steps...]`
+
+// errSampleFailed is returned by the sample run command if a step failed. The report contains the error.
+var errSampleFailed = errors.New("sample run failed")
+
+// sampleReport is the JSON report of the sample run command.
+type sampleReport struct {
+	Passed   bool                `json:"passed"`
+	Duration float64             `json:"duration_seconds"`
+	Steps    []*sampleStepReport `json:"steps"`
+}
+
+// sampleStepReport is the outcome of a step. Steps after a failed step are skipped, as they depend on its data.
+type sampleStepReport struct {
+	Name       string  `json:"name"`
+	Outcome    string  `json:"outcome"`
+	Error      string  `json:"error,omitempty"`
+	Duration   float64 `json:"duration_seconds"`
+	Statements int64   `json:"statements"`
+}
+
+// sampleCommand runs the sample subcommand with the arguments after "sample".
+func sampleCommand(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(sampleUsage, appName)
+	}
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			return fmt.Errorf(sampleUsage, appName)
+		}
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		for _, step := range sampleSteps {
+			fmt.Fprintf(tw, "%s\t%s\n", step.name, step.description)
+		}
+		return tw.Flush()
+	case "run":
+		cfg, names, err := loadConfigWithArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		steps, err := selectSampleSteps(names)
+		if err != nil {
+			return err
+		}
+		return runSampleSteps(cfg, steps)
+	default:
+		return fmt.Errorf(sampleUsage, appName)
+	}
+}
+
+// selectSampleSteps returns the steps with the given names in the order of sampleSteps, or all steps if no names are
+// given.
+func selectSampleSteps(names []string) ([]sampleStep, error) {
+	if len(names) == 0 {
+		return sampleSteps, nil
+	}
+	selected := map[string]bool{}
+	for _, name := range names {
+		selected[name] = true
+	}
+	var steps []sampleStep
+	for _, step := range sampleSteps {
+		if selected[step.name] {
+			steps = append(steps, step)
+			delete(selected, step.name)
+		}
+	}
+	if len(selected) > 0 {
+		var unknown []string
+		for _, name := range names {
+			if selected[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		return nil, fmt.Errorf("unknown sample steps: %s (see %s sample list)", strings.Join(unknown, ", "), appName)
+	}
+	return steps, nil
+}
+
+// runSampleSteps executes the steps on the database of cfg and writes the report to cfg.SampleReport. The console
+// output of the steps is written to stderr if the report is written to stdout.
+func runSampleSteps(cfg *Config, steps []sampleStep) error {
+	report := os.Stdout
+	if cfg.SampleReport != "-" {
+		f, err := os.Create(cfg.SampleReport)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		report = f
+	} else {
+		defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+		os.Stdout = os.Stderr
+	}
+
+	db, err := newDbConn(cfg, logger.Default.LogMode(logger.Error))
+	if err != nil {
+		return err
+	}
+	defer closeDbConn(db)
+	counter := &statementCounter{}
+	if err := db.Use(counter); err != nil {
+		return err
+	}
+	dataModelFile = cfg.SampleDataModel
+	if err := configureDataGen(cfg); err != nil {
+		return err
+	}
+
+	result := executeSampleSteps(db, steps, counter)
+	encoder := json.NewEncoder(report)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	if !result.Passed {
+		return errSampleFailed
+	}
+	return nil
+}
+
+// executeSampleSteps executes the steps until a step fails, and returns the report.
+func executeSampleSteps(db *gorm.DB, steps []sampleStep, counter *statementCounter) *sampleReport {
+	result := &sampleReport{Passed: true}
+	start := time.Now()
+	for _, step := range steps {
+		stepReport := &sampleStepReport{Name: step.name, Outcome: "skipped"}
+		result.Steps = append(result.Steps, stepReport)
+		if !result.Passed {
+			continue
+		}
+		statements := counter.count()
+		begin := time.Now()
+		err := step.run(db)
+		stepReport.Duration = time.Since(begin).Seconds()
+		stepReport.Statements = counter.count() - statements
+		stepReport.Outcome = "passed"
+		if err != nil {
+			stepReport.Outcome, stepReport.Error = "failed", err.Error()
+			result.Passed = false
+		}
+	}
+	result.Duration = time.Since(start).Seconds()
+	return result
+}
+
+// statementCounter is a GORM plugin that counts the executed statements.
+type statementCounter struct {
+	n int64
+}
+
+func (*statementCounter) Name() string {
+	return "statement_counter"
+}
+
+func (p *statementCounter) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().After("gorm:create").Register("statement_counter:create", p.add),
+		cb.Query().After("gorm:query").Register("statement_counter:query", p.add),
+		cb.Update().After("gorm:update").Register("statement_counter:update", p.add),
+		cb.Delete().After("gorm:delete").Register("statement_counter:delete", p.add),
+		cb.Row().After("gorm:row").Register("statement_counter:row", p.add),
+		cb.Raw().After("gorm:raw").Register("statement_counter:raw", p.add),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add counts the statement of db if it was built, as gorm skips statements after an error.
+func (p *statementCounter) add(db *gorm.DB) {
+	if db.Statement.SQL.Len() > 0 {
+		atomic.AddInt64(&p.n, 1)
+	}
+}
+
+func (p *statementCounter) count() int64 {
+	return atomic.LoadInt64(&p.n)
+}
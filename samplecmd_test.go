@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+func TestExecuteSampleSteps(t *testing.T) {
+	server, db := newFakeDb(t)
+	counter := &statementCounter{}
+	if err := db.Use(counter); err != nil {
+		t.Fatal(err)
+	}
+	server.On("DELETE FROM albums", pgfake.Error("42P01", "relation albums does not exist"))
+
+	steps, err := selectSampleSteps([]string{"PrintConcerts", "DeleteAllData"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps = append(steps, sampleSteps[len(sampleSteps)-1])
+	report := executeSampleSteps(db, steps, counter)
+	if report.Passed {
+		t.Error("got passed, want failed")
+	}
+	want := []sampleStepReport{
+		{Name: "DeleteAllData", Outcome: "failed", Error: "ERROR: relation albums does not exist (SQLSTATE 42P01)", Statements: 3},
+		{Name: "PrintConcerts", Outcome: "skipped"},
+		{Name: "QueryWithTimeout", Outcome: "skipped"},
+	}
+	if len(report.Steps) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(report.Steps), len(want))
+	}
+	for i, step := range report.Steps {
+		step.Duration = 0
+		if *step != want[i] {
+			t.Errorf("step %d: got %+v, want %+v", i, *step, want[i])
+		}
+	}
+}
+
+func TestSelectUnknownSampleSteps(t *testing.T) {
+	if _, err := selectSampleSteps([]string{"PrintConcerts", "PrintVenues"}); err == nil {
+		t.Fatal("got no error for an unknown step")
+	}
+}
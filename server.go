@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+// serverState tracks whether the server accepts new traffic. It is shared by all copies of MusicDbOperation.
+type serverState struct {
+	draining int32
+}
+
+func (s *serverState) startDraining() {
+	atomic.StoreInt32(&s.draining, 1)
+}
+
+func (s *serverState) isDraining() bool {
+	return atomic.LoadInt32(&s.draining) == 1
+}
+
+// serve runs the HTTP and gRPC servers until ctx is cancelled, typically by SIGTERM, and then shuts down in order:
+//  1. readiness reports unhealthy, while requests continue to be served for the configured shutdown delay,
+//  2. the listeners are closed and in-flight requests and transactions get the drain period to finish,
+//  3. the database connection pool is closed.
+func serve(ctx context.Context, cfg *Config, handler http.Handler, grpcServer *grpc.Server, m MusicDbOperation, log zerolog.Logger) error {
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// The gRPC server runs on its own port in the same binary and shares the database connection.
+	lis, err := net.Listen("tcp", ":"+cfg.GrpcPort)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			errs <- err
+		}
+	}()
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+	log.Info().Str("port", cfg.Port).Str("grpc_port", cfg.GrpcPort).Msg("server started")
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		log.Info().Dur("shutdown_delay", cfg.ShutdownDelay).Dur("drain_period", cfg.DrainPeriod).Msg("shutting down")
+	case serveErr = <-errs:
+		log.Error().Err(serveErr).Msg("server failed, shutting down")
+	}
+
+	m.state.startDraining()
+	if serveErr == nil && cfg.ShutdownDelay > 0 {
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.DrainPeriod)
+	defer cancel()
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	if err := httpServer.Shutdown(drainCtx); err != nil {
+		log.Warn().Err(err).Msg("HTTP requests did not finish within the drain period")
+	}
+	select {
+	case <-grpcStopped:
+	case <-drainCtx.Done():
+		log.Warn().Msg("gRPC calls did not finish within the drain period")
+		grpcServer.Stop()
+	}
+
+	if err := m.tenants.close(); err != nil {
+		log.Error().Err(err).Msg("failed to close the connection pools of the tenants")
+	}
+	if err := closeDbConn(m.db); err != nil {
+		log.Error().Err(err).Msg("failed to close the database connection pool")
+	} else {
+		log.Info().Msg("closed the database connection pool")
+	}
+	return serveErr
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// maxStatementParams is the maximum number of parameters in a prepared statement that PGAdapter supports.
+const maxStatementParams = 50
+
+// errUnsupportedStatement is returned by the spannerGuard in strict mode for statements that Cloud Spanner or
+// PGAdapter do not support.
+var errUnsupportedStatement = errors.New("statement is not supported by Cloud Spanner")
+
+var spannerGuardViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "spanner_guard_violations_total",
+	Help: "Number of statements with a construct that is not supported by Cloud Spanner, by rule and action (warn or reject).",
+}, []string{"rule", "action"})
+
+// spannerGuardRule is a construct that Cloud Spanner or PGAdapter do not support, with a hint how to avoid it.
+type spannerGuardRule struct {
+	name    string
+	pattern *regexp.Regexp
+	hint    string
+}
+
+var spannerGuardRules = []spannerGuardRule{
+	{
+		name:    "on_conflict",
+		pattern: regexp.MustCompile(`(?i)\bON\s+CONFLICT\b`),
+		hint: "Cloud Spanner does not support INSERT ... ON CONFLICT. GORM generates it when a record is created or saved " +
+			"with associations: create the associated records separately, or use Omit(clause.Associations)",
+	},
+	{
+		name:    "savepoint",
+		pattern: regexp.MustCompile(`(?i)^\s*(SAVEPOINT|RELEASE|ROLLBACK\s+TO)\b`),
+		hint: "Cloud Spanner does not support savepoints. GORM uses them for nested transactions: set " +
+			"DisableNestedTransaction in the gorm.Config",
+	},
+	{
+		name:    "returning",
+		pattern: regexp.MustCompile(`(?i)\bRETURNING\b`),
+		hint: "Cloud Spanner does not return generated columns with RETURNING. GORM adds it for fields with a default " +
+			"value that is set by the database, such as default:(-): remove the default and reload the record instead",
+	},
+}
+
+// spannerGuard is a GORM plugin that checks each statement for constructs that Cloud Spanner or PGAdapter do not
+// support before it is sent to PGAdapter, so that they fail with a hint instead of an opaque error of the backend.
+// Violations are logged as warnings, or rejected with errUnsupportedStatement in strict mode.
+//
+// The plugin wraps the connection pool of the database, as GORM only generates the SQL right before it executes it.
+// It must therefore be installed after the connection pool is configured.
+type spannerGuard struct {
+	strict bool
+}
+
+func (spannerGuard) Name() string {
+	return "spanner_guard"
+}
+
+func (p spannerGuard) Initialize(db *gorm.DB) error {
+	if !db.Config.DisableNestedTransaction {
+		if p.strict {
+			return fmt.Errorf("%w: nested transactions use savepoints, set DisableNestedTransaction in the gorm.Config", errUnsupportedStatement)
+		}
+		db.Logger.Warn(context.Background(), "spanner guard: nested transactions use savepoints, which Cloud Spanner does not support")
+	}
+	pool := &guardedDB{guardedConnPool{pool: db.ConnPool, guard: p, log: db.Logger}}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return nil
+}
+
+// check returns an error if query uses an unsupported construct in strict mode, and logs the violations otherwise.
+func (p spannerGuard) check(ctx context.Context, log logger.Interface, query string, numParams int) error {
+	var violations []string
+	report := func(rule, hint string) {
+		violations = append(violations, hint)
+		action := "warn"
+		if p.strict {
+			action = "reject"
+		}
+		spannerGuardViolations.WithLabelValues(rule, action).Inc()
+	}
+	for _, rule := range spannerGuardRules {
+		if rule.pattern.MatchString(query) {
+			report(rule.name, rule.hint)
+		}
+	}
+	if numParams > maxStatementParams {
+		report("max_params", fmt.Sprintf("the statement has %d parameters, but PGAdapter supports at most %d: "+
+			"use CreateInBatches with a smaller batch size, or split the IN list", numParams, maxStatementParams))
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	if p.strict {
+		return fmt.Errorf("%w: %s", errUnsupportedStatement, violations[0])
+	}
+	for _, v := range violations {
+		log.Warn(ctx, "spanner guard: %s: %s", v, query)
+	}
+	return nil
+}
+
+// placeholders matches the parameters of a statement.
+var placeholders = regexp.MustCompile(`\$(\d+)`)
+
+// countParams returns the number of parameters of a statement that is prepared without arguments.
+func countParams(query string) int {
+	n := 0
+	for _, m := range placeholders.FindAllStringSubmatch(query, -1) {
+		if i, _ := strconv.Atoi(m[1]); i > n {
+			n = i
+		}
+	}
+	return n
+}
+
+// guardedConnPool checks each statement with the spannerGuard before it passes it to the wrapped pool or
+// transaction.
+type guardedConnPool struct {
+	pool  gorm.ConnPool
+	guard spannerGuard
+	log   logger.Interface
+}
+
+// guardedDB is the guardedConnPool of the database, guardedTx the guardedConnPool of a transaction that it started.
+// Only guardedTx is a gorm.TxCommitter, as gorm treats transactions on a TxCommitter as nested transactions.
+type (
+	guardedDB struct{ guardedConnPool }
+	guardedTx struct{ guardedConnPool }
+)
+
+var (
+	_ gorm.ConnPoolBeginner = (*guardedDB)(nil)
+	_ gorm.GetDBConnector   = (*guardedDB)(nil)
+	_ gorm.ConnPool         = (*guardedTx)(nil)
+	_ gorm.TxCommitter      = (*guardedTx)(nil)
+)
+
+func (p *guardedConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if err := p.guard.check(ctx, p.log, query, countParams(query)); err != nil {
+		return nil, err
+	}
+	return p.pool.PrepareContext(ctx, query)
+}
+
+func (p *guardedConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := p.guard.check(ctx, p.log, query, len(args)); err != nil {
+		return nil, err
+	}
+	return p.pool.ExecContext(ctx, query, args...)
+}
+
+func (p *guardedConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := p.guard.check(ctx, p.log, query, len(args)); err != nil {
+		return nil, err
+	}
+	return p.pool.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext cannot return an error before the statement is executed, so violations are only logged, also in
+// strict mode.
+func (p *guardedConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if err := p.guard.check(ctx, p.log, query, len(args)); err != nil {
+		p.log.Error(ctx, "spanner guard: %v: %s", err, query)
+	}
+	return p.pool.QueryRowContext(ctx, query, args...)
+}
+
+func (p *guardedDB) GetDBConn() (*sql.DB, error) {
+	if db, ok := p.pool.(*sql.DB); ok {
+		return db, nil
+	}
+	if connector, ok := p.pool.(gorm.GetDBConnector); ok {
+		return connector.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+func (p *guardedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	var (
+		tx  gorm.ConnPool
+		err error
+	)
+	switch beginner := p.pool.(type) {
+	case gorm.TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case gorm.ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &guardedTx{guardedConnPool{pool: tx, guard: p.guard, log: p.log}}, nil
+}
+
+func (tx *guardedTx) Commit() error {
+	return tx.pool.(gorm.TxCommitter).Commit()
+}
+
+func (tx *guardedTx) Rollback() error {
+	return tx.pool.(gorm.TxCommitter).Rollback()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+func TestSpannerGuardRejectsInStrictMode(t *testing.T) {
+	server, db := newFakeDb(t)
+	if err := db.Use(spannerGuard{strict: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A batch of 10 tracks has 60 parameters.
+	_, tracks := newAlbumWithRandomTracks(sampleData, "album1", "Album", 10)
+	err := runTransaction(db, func(tx *gorm.DB) error {
+		if err := tx.Create(&Venue{BaseModel: BaseModel{ID: "v1"}, Name: "Venue"}).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(tracks, 10).Error
+	})
+	if !errors.Is(err, errUnsupportedStatement) {
+		t.Fatalf("got error %v, want %v", err, errUnsupportedStatement)
+	}
+	if got := len(statementsWith(server, `INSERT INTO "venues"`)); got != 1 {
+		t.Errorf("venue inserts: got %d, want 1", got)
+	}
+	if got := len(statementsWith(server, `INSERT INTO "tracks"`)); got != 0 {
+		t.Errorf("got %d track inserts, want none", got)
+	}
+	if got := len(statementsWith(server, "rollback")); got != 1 {
+		t.Errorf("rollbacks: got %d, want 1", got)
+	}
+
+	err = db.Exec(`INSERT INTO venues (id, name) VALUES ('v1', 'Venue') ON CONFLICT (id) DO NOTHING`).Error
+	if !errors.Is(err, errUnsupportedStatement) {
+		t.Fatalf("got error %v, want %v", err, errUnsupportedStatement)
+	}
+}
+
+func TestSpannerGuardWarns(t *testing.T) {
+	server, db := newFakeDb(t)
+	if err := db.Use(spannerGuard{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, tracks := newAlbumWithRandomTracks(sampleData, "album1", "Album", 10)
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(tracks, 10).Error
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(statementsWith(server, `INSERT INTO "tracks"`)); got != 1 {
+		t.Errorf("track inserts: got %d, want 1", got)
+	}
+	if got := len(statementsWith(server, "begin")); got != 1 {
+		t.Errorf("transactions: got %d, want 1", got)
+	}
+}
+
+func TestSpannerGuardRejectsReturning(t *testing.T) {
+	server, db := newFakeDb(t)
+	if err := db.Use(spannerGuard{strict: true}); err != nil {
+		t.Fatal(err)
+	}
+	// GORM reads fields with a default value of the database back with RETURNING.
+	type generatedName struct {
+		ID   string
+		Name string `gorm:"->;default:(-)"`
+	}
+	if err := db.Create(&generatedName{ID: "g1"}).Error; !errors.Is(err, errUnsupportedStatement) {
+		t.Fatalf("got error %v, want %v", err, errUnsupportedStatement)
+	}
+
+	// The sample reloads the singer to get the generated full name instead.
+	server.On(`SELECT * FROM "singers"`, pgfake.Rows([]pgfake.Column{{Name: "id", OID: pgtype.TextOID},
+		{Name: "full_name", OID: pgtype.TextOID}}, []interface{}{"s1", "Alice Smith"}))
+	if _, err := CreateSinger(db, "Alice", "Smith"); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(statementsWith(server, "RETURNING")); got != 0 {
+		t.Errorf("got %d statements with RETURNING, want none", got)
+	}
+}
+
+func TestSpannerGuardRequiresDisableNestedTransaction(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunPool{&sqlRecorder{}}}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(spannerGuard{strict: true}); !errors.Is(err, errUnsupportedStatement) {
+		t.Fatalf("got error %v, want %v", err, errUnsupportedStatement)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+	"github.com/shopspring/decimal"
+)
+
+// SingerStats holds the aggregated album, track and marketing budget numbers of one singer.
+type SingerStats struct {
+	SingerId   string `json:"singer_id"`
+	FullName   string `json:"full_name"`
+	AlbumCount int64  `json:"album_count"`
+	TrackCount int64  `json:"track_count"`
+	// TotalBudget is summed by the database as a numeric, so no float rounding is involved.
+	// It is null if none of the albums of the singer has a marketing budget.
+	TotalBudget decimal.NullDecimal `json:"total_marketing_budget"`
+	// AverageBudget is computed from TotalBudget and the number of albums that have a budget.
+	AverageBudget decimal.NullDecimal `json:"average_marketing_budget"`
+	// BudgetCount is the number of albums with a non-null marketing budget.
+	BudgetCount int64 `json:"-"`
+}
+
+// SampleRateBucket is one bucket of the sample rate histogram. The bucket covers the
+// range [LowerBound, LowerBound + bucket width).
+type SampleRateBucket struct {
+	LowerBound float64 `json:"lower_bound"`
+	TrackCount int64   `json:"track_count"`
+}
+
+// SampleRateStats describes the distribution of the sample rate of all tracks.
+type SampleRateStats struct {
+	TrackCount  int64   `json:"track_count"`
+	Average     float64 `json:"average"`
+	Min         float64 `json:"min"`
+	Max         float64 `json:"max"`
+	BucketWidth float64 `json:"bucket_width"`
+	// Buckets are queried separately, so GORM must not treat them as a relation when it scans the totals.
+	Buckets []SampleRateBucket `json:"buckets" gorm:"-"`
+}
+
+// DecadeStats holds the number of albums released in a decade.
+type DecadeStats struct {
+	Decade     int64 `json:"decade"`
+	AlbumCount int64 `json:"album_count"`
+}
+
+const defaultSampleRateBucketWidth = 5.0
+
+func (m MusicDbOperation) getSingerStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := m.repoFor(r.Context()).SingerStats(r.Context())
+	if err != nil {
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	render.JSON(w, r, stats)
+}
+
+func (r *gormRepository) SingerStats(ctx context.Context) ([]*SingerStats, error) {
+	stats := []*SingerStats{}
+	// Tracks are counted in a derived table before joining, as joining the tracks directly would
+	// multiply the marketing budget of each album by the number of tracks of the album.
+	if err := r.conn(ctx).Raw(`SELECT singers.id AS singer_id, singers.full_name AS full_name,
+			count(albums.id) AS album_count,
+			coalesce(sum(album_tracks.track_count), 0) AS track_count,
+			sum(albums.marketing_budget) AS total_budget,
+			count(albums.marketing_budget) AS budget_count
+		FROM singers
+		LEFT JOIN albums ON albums.singer_id = singers.id
+		LEFT JOIN (SELECT id, count(1) AS track_count FROM tracks GROUP BY id) AS album_tracks ON album_tracks.id = albums.id
+		GROUP BY singers.id, singers.full_name
+		ORDER BY singers.full_name, singers.id`).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	setAverageBudgets(stats)
+	return stats, nil
+}
+
+// setAverageBudgets computes the average marketing budget of each singer from the total budget and the number of
+// albums with a budget.
+func setAverageBudgets(stats []*SingerStats) {
+	for _, s := range stats {
+		if s.TotalBudget.Valid && s.BudgetCount > 0 {
+			s.AverageBudget = decimal.NullDecimal{
+				Decimal: s.TotalBudget.Decimal.Div(decimal.NewFromInt(s.BudgetCount)),
+				Valid:   true,
+			}
+		}
+	}
+}
+
+func (m MusicDbOperation) getSampleRateStats(w http.ResponseWriter, r *http.Request) {
+	bucketWidth := defaultSampleRateBucketWidth
+	if v := r.URL.Query().Get("bucket_width"); v != "" {
+		width, err := strconv.ParseFloat(v, 64)
+		if err != nil || width <= 0 {
+			errorRender(w, r, http.StatusBadRequest, errors.New("bucket_width must be a positive number"))
+			return
+		}
+		bucketWidth = width
+	}
+	stats, err := m.repoFor(r.Context()).SampleRateStats(r.Context(), bucketWidth)
+	if err != nil {
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	render.JSON(w, r, stats)
+}
+
+func (r *gormRepository) SampleRateStats(ctx context.Context, bucketWidth float64) (*SampleRateStats, error) {
+	db := r.conn(ctx)
+	stats := &SampleRateStats{BucketWidth: bucketWidth, Buckets: []SampleRateBucket{}}
+	if err := db.Raw(`SELECT count(1) AS track_count,
+			coalesce(avg(sample_rate), 0) AS average,
+			coalesce(min(sample_rate), 0) AS min,
+			coalesce(max(sample_rate), 0) AS max
+		FROM tracks`).Scan(stats).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Raw(`SELECT floor(sample_rate / @width) * @width AS lower_bound, count(1) AS track_count
+		FROM tracks
+		GROUP BY 1
+		ORDER BY 1`, map[string]interface{}{"width": bucketWidth}).Scan(&stats.Buckets).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (m MusicDbOperation) getAlbumsPerDecade(w http.ResponseWriter, r *http.Request) {
+	stats, err := m.repoFor(r.Context()).AlbumsPerDecade(r.Context())
+	if err != nil {
+		errorRender(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	render.JSON(w, r, stats)
+}
+
+func (r *gormRepository) AlbumsPerDecade(ctx context.Context) ([]*DecadeStats, error) {
+	stats := []*DecadeStats{}
+	if err := r.conn(ctx).Raw(`SELECT cast(floor(extract(year from release_date) / 10) * 10 AS bigint) AS decade, count(1) AS album_count
+		FROM albums
+		WHERE release_date IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1`).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
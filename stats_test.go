@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+func TestSingerStatsSumBudgetsExactly(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	for _, err := range []error{
+		repo.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: "s1"}, LastName: "A"}),
+		repo.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: "s2"}, LastName: "B"}),
+		repo.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: "s3"}, LastName: "C"}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a1"}, SingerId: "s1",
+			MarketingBudget: decimal.NewNullDecimal(decimal.RequireFromString("0.1"))}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a2"}, SingerId: "s1",
+			MarketingBudget: decimal.NewNullDecimal(decimal.RequireFromString("0.2"))}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a3"}, SingerId: "s1"}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a4"}, SingerId: "s2"}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The database sums the budgets as numerics, so the GORM repository gets the exact sum.
+	server, db := newFakeDb(t)
+	server.On("AS budget_count", pgfake.Rows([]pgfake.Column{
+		{Name: "singer_id", OID: pgtype.TextOID}, {Name: "full_name", OID: pgtype.TextOID},
+		{Name: "album_count", OID: pgtype.Int8OID}, {Name: "track_count", OID: pgtype.Int8OID},
+		{Name: "total_budget", OID: pgtype.NumericOID}, {Name: "budget_count", OID: pgtype.Int8OID},
+	},
+		[]interface{}{"s1", "A", 3, 0, "0.3", 2},
+		[]interface{}{"s2", "B", 1, 0, nil, 0},
+		[]interface{}{"s3", "C", 0, 0, nil, 0},
+	))
+
+	for name, r := range map[string]MusicRepository{"memory": repo, "gorm": newGormRepository(db)} {
+		stats, err := r.SingerStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats) != 3 {
+			t.Fatalf("%s repository: got %d singers, want 3", name, len(stats))
+		}
+		got, err := json.Marshal(stats)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Singers without albums or without budgets have no total and no average.
+		want := `[{"singer_id":"s1","full_name":"A","album_count":3,"track_count":0,"total_marketing_budget":"0.3","average_marketing_budget":"0.15"},` +
+			`{"singer_id":"s2","full_name":"B","album_count":1,"track_count":0,"total_marketing_budget":null,"average_marketing_budget":null},` +
+			`{"singer_id":"s3","full_name":"C","album_count":0,"track_count":0,"total_marketing_budget":null,"average_marketing_budget":null}]`
+		if string(got) != want {
+			t.Errorf("%s repository: got %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestAlbumsPerDecadeSkipAlbumsWithoutReleaseDate(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRepository()
+	date := func(year int) datatypes.Date { return datatypes.Date(time.Date(year, 3, 1, 0, 0, 0, 0, time.UTC)) }
+	for _, err := range []error{
+		repo.CreateSinger(ctx, &Singer{BaseModel: BaseModel{ID: "s1"}, LastName: "A"}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a1"}, SingerId: "s1", ReleaseDate: date(1995)}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a2"}, SingerId: "s1", ReleaseDate: date(1999)}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a3"}, SingerId: "s1", ReleaseDate: date(2004)}),
+		repo.CreateAlbum(ctx, &Album{BaseModel: BaseModel{ID: "a4"}, SingerId: "s1"}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The database does not count albums with a NULL release date.
+	server, db := newFakeDb(t)
+	server.On("AS decade", pgfake.Rows([]pgfake.Column{{Name: "decade", OID: pgtype.Int8OID}, {Name: "album_count", OID: pgtype.Int8OID}},
+		[]interface{}{1990, 2}, []interface{}{2000, 1}))
+
+	for name, r := range map[string]MusicRepository{"memory": repo, "gorm": newGormRepository(db)} {
+		stats, err := r.AlbumsPerDecade(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.Marshal(stats)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `[{"decade":1990,"album_count":2},{"decade":2000,"album_count":1}]`; string(got) != want {
+			t.Errorf("%s repository: got %s, want %s", name, got, want)
+		}
+	}
+	if got := len(statementsWith(server, "release_date IS NOT NULL")); got != 1 {
+		t.Errorf("got %d queries that skip albums without a release date, want 1", got)
+	}
+}
+
+func TestStatsOfAnEmptyCatalogAreEmptyLists(t *testing.T) {
+	server, db := newFakeDb(t)
+	server.On("AS budget_count", pgfake.Rows([]pgfake.Column{{Name: "singer_id", OID: pgtype.TextOID}}))
+	server.On("AS decade", pgfake.Rows([]pgfake.Column{{Name: "decade", OID: pgtype.Int8OID}}))
+	server.On("AS lower_bound", pgfake.Rows([]pgfake.Column{{Name: "lower_bound", OID: pgtype.Float8OID}}))
+	server.On("AS average", pgfake.Rows([]pgfake.Column{{Name: "track_count", OID: pgtype.Int8OID}}, []interface{}{0}))
+
+	for name, repo := range map[string]MusicRepository{"memory": newMemoryRepository(), "gorm": newGormRepository(db)} {
+		m := MusicDbOperation{repo: repo, cfg: defaultConfig()}
+		for _, tc := range []struct {
+			path    string
+			handler http.HandlerFunc
+			want    string
+		}{
+			{"/api/stats/singers", m.getSingerStats, `[]`},
+			{"/api/stats/albums-per-decade", m.getAlbumsPerDecade, `[]`},
+			{"/api/stats/sample-rates", m.getSampleRateStats, `"buckets":[]`},
+		} {
+			w := httptest.NewRecorder()
+			tc.handler(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), tc.want) {
+				t.Errorf("%s repository, %s: got status %d, %s, want %s", name, tc.path, w.Code, w.Body, tc.want)
+			}
+		}
+	}
+}
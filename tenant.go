@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/httplog"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// defaultTenant is the tenant of requests that do not select a tenant. It uses the database of the main configuration.
+const defaultTenant = "default"
+
+// TenantConfig is the configuration of the database of one tenant.
+type TenantConfig struct {
+	// ConnString is the PGAdapter connection string of the tenant. If empty, it is derived from DatabaseName and
+	// the project and instance of the main configuration. The database is selected either by ConnString or by
+	// DatabaseName, which is then added to ConnString.
+	ConnString   string `yaml:"connection_string" secret:"dsn"`
+	DatabaseName string `yaml:"database_name"`
+	// Pool settings of the tenant. Zero means the value of the main configuration.
+	MaxOpenConns int `yaml:"max_open_conns"`
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// MaxInFlight is the maximum number of concurrent requests of the tenant, 0 for unlimited.
+	MaxInFlight int `yaml:"max_in_flight"`
+	// Subjects are the API key subjects and JWT subjects that may use the tenant if authentication is enabled.
+	// Subjects that are bound to a tenant cannot use the main database.
+	Subjects []string `yaml:"subjects"`
+}
+
+// tenant is a tenant with an open connection pool.
+type tenant struct {
+	name     string
+	db       *gorm.DB
+	repo     MusicRepository
+	inFlight chan struct{}
+}
+
+// tenantSelection is the tenant that a request selects. The name is resolved before the caller is authenticated, and
+// the tenant is only set once the caller may use it and its connection pool is open.
+type tenantSelection struct {
+	name string
+	t    *tenant
+}
+
+type tenantKey struct{}
+
+func withTenant(ctx context.Context, t *tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, &tenantSelection{name: t.name, t: t})
+}
+
+// tenantFrom returns the tenant of the request of ctx, or nil if the request uses the main database.
+func tenantFrom(ctx context.Context) *tenant {
+	if ctx == nil {
+		return nil
+	}
+	if sel, ok := ctx.Value(tenantKey{}).(*tenantSelection); ok {
+		return sel.t
+	}
+	return nil
+}
+
+// tenantName returns the name of the tenant of the request of ctx.
+func tenantName(ctx context.Context) string {
+	if t := tenantFrom(ctx); t != nil {
+		return t.name
+	}
+	return defaultTenant
+}
+
+var errUnknownTenant = errors.New("unknown tenant")
+
+// tenantOpening is the connection pool of a tenant that is being opened. done is closed once t or err is set.
+type tenantOpening struct {
+	done chan struct{}
+	t    *tenant
+	err  error
+}
+
+// tenantRouter resolves the tenant of a request and opens the connection pool of a tenant on its first request.
+// Each tenant has its own pool, so that a tenant cannot use up the connections of other tenants.
+type tenantRouter struct {
+	cfg *Config
+	log zerolog.Logger
+	// subjects maps the subjects that are bound to tenants to their tenants.
+	subjects map[string]map[string]bool
+	mu       sync.Mutex
+	tenants  map[string]*tenant
+	opening  map[string]*tenantOpening
+}
+
+func newTenantRouter(cfg *Config, log zerolog.Logger) *tenantRouter {
+	tr := &tenantRouter{cfg: cfg, log: log, subjects: map[string]map[string]bool{}, tenants: map[string]*tenant{},
+		opening: map[string]*tenantOpening{}}
+	for name, tc := range cfg.Tenants {
+		for _, subject := range tc.Subjects {
+			if tr.subjects[subject] == nil {
+				tr.subjects[subject] = map[string]bool{}
+			}
+			tr.subjects[subject][name] = true
+		}
+	}
+	return tr
+}
+
+var errTenantNotAllowed = errors.New("tenant not allowed")
+
+// checkAccess returns errTenantNotAllowed if the caller of the request of ctx may not use the tenant with the given
+// name. Callers that are bound to tenants may only use these tenants, and all other callers only the main database.
+// If authentication is disabled, every caller may use every tenant.
+func (tr *tenantRouter) checkAccess(ctx context.Context, name string) error {
+	id := identityFrom(ctx)
+	if id == anonymous {
+		return nil
+	}
+	var tenants map[string]bool
+	if id != nil {
+		tenants = tr.subjects[id.Subject]
+	}
+	if name == defaultTenant && len(tenants) == 0 || tenants[name] {
+		return nil
+	}
+	if name == defaultTenant {
+		return fmt.Errorf("%w: the caller is bound to a tenant and must select it", errTenantNotAllowed)
+	}
+	return fmt.Errorf("%w: %s", errTenantNotAllowed, name)
+}
+
+// resolve returns the name of the tenant that r selects with the tenant header or the subdomain of the tenant
+// domain, or an empty string if r does not select a tenant.
+func (tr *tenantRouter) resolve(r *http.Request) string {
+	if name := r.Header.Get(tr.cfg.TenantHeader); name != "" {
+		return strings.ToLower(name)
+	}
+	if tr.cfg.TenantDomain == "" {
+		return ""
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if sub := strings.TrimSuffix(strings.ToLower(host), "."+tr.cfg.TenantDomain); sub != host && !strings.Contains(sub, ".") {
+		return sub
+	}
+	return ""
+}
+
+// tenant returns the tenant with the given name and opens its connection pool if this is its first request. It
+// returns the error of ctx if ctx is done before the pool is open.
+func (tr *tenantRouter) tenant(ctx context.Context, name string) (*tenant, error) {
+	tc, ok := tr.cfg.Tenants[name]
+	if !ok {
+		return nil, errUnknownTenant
+	}
+	tr.mu.Lock()
+	if t, ok := tr.tenants[name]; ok {
+		tr.mu.Unlock()
+		return t, nil
+	}
+	// The pool is opened without holding the lock, so that a slow tenant does not block the requests of other
+	// tenants. Concurrent first requests of the tenant wait for the same opening, which is bounded by the connect
+	// timeout and continues if the requests give up.
+	o, ok := tr.opening[name]
+	if !ok {
+		o = &tenantOpening{done: make(chan struct{})}
+		tr.opening[name] = o
+		go func() {
+			o.t, o.err = tr.open(name, tc)
+			tr.mu.Lock()
+			// Failed openings are forgotten, so that the next request tries again.
+			delete(tr.opening, name)
+			if o.err == nil {
+				tr.tenants[name] = o.t
+			}
+			tr.mu.Unlock()
+			close(o.done)
+		}()
+	}
+	tr.mu.Unlock()
+	select {
+	case <-o.done:
+		return o.t, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// open opens the connection pool of the tenant with the given name.
+func (tr *tenantRouter) open(name string, tc TenantConfig) (*tenant, error) {
+	// The tenant configuration overrides the database and pool settings of a copy of the main configuration.
+	cfg := *tr.cfg
+	cfg.ConnString, cfg.DatabaseName = tc.ConnString, tc.DatabaseName
+	if tc.MaxOpenConns > 0 {
+		cfg.MaxOpenConns = tc.MaxOpenConns
+	}
+	if tc.MaxIdleConns > 0 {
+		cfg.MaxIdleConns = tc.MaxIdleConns
+	}
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		DisableNestedTransaction: true,
+		DisableAutomaticPing:     true,
+		Logger:                   newJsonGormLogger(tr.log.With().Str("tenant", name).Logger(), &cfg),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the database of tenant %s: %w", name, err)
+	}
+	if err := ping(db, cfg.ConnectTimeout); err != nil {
+		closeDbConn(db)
+		return nil, fmt.Errorf("could not connect to the database of tenant %s: %w", name, err)
+	}
+	if err := configureConnPool(db, &cfg); err != nil {
+		closeDbConn(db)
+		return nil, err
+	}
+	if err := instrumentDb(db, name); err != nil {
+		closeDbConn(db)
+		return nil, err
+	}
+	t := &tenant{name: name, db: db, repo: newGormRepository(db)}
+	if tc.MaxInFlight > 0 {
+		t.inFlight = make(chan struct{}, tc.MaxInFlight)
+	}
+	tr.log.Info().Str("tenant", name).Str("database", cfg.DatabaseName).Msg("opened the connection pool of the tenant")
+	return t, nil
+}
+
+// ping checks that the database of db can be reached within timeout.
+func ping(db *gorm.DB, timeout time.Duration) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// close closes the connection pools of all tenants.
+func (tr *tenantRouter) close() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	var errs []string
+	for name, t := range tr.tenants {
+		if err := closeDbConn(t.db); err != nil {
+			errs = append(errs, name+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New("failed to close tenant connection pools: " + strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// middleware resolves the name of the tenant that the request selects. The tenant is only looked up by selectTenant,
+// once the caller has been authenticated, so that unauthenticated requests can neither open connection pools nor
+// find out which tenants exist.
+func (tr *tenantRouter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := tr.resolve(r)
+		if name == "" || name == defaultTenant {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, &tenantSelection{name: name})))
+	})
+}
+
+// selectTenant rejects requests with 403 if the caller may not use the tenant that the request selects. Otherwise it
+// opens the connection pool of the tenant, so that repoFor returns its repository, and adds the tenant to the request
+// log and the trace. Requests for unknown tenants are rejected with 404, and requests over the concurrency limit of
+// the tenant with 503. It must run after the authentication middleware.
+func (tr *tenantRouter) selectTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sel, _ := r.Context().Value(tenantKey{}).(*tenantSelection)
+		name := defaultTenant
+		if sel != nil {
+			name = sel.name
+		}
+		if err := tr.checkAccess(r.Context(), name); err != nil {
+			errorRender(w, r, http.StatusForbidden, err)
+			return
+		}
+		if sel == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		t, err := tr.tenant(r.Context(), name)
+		if errors.Is(err, errUnknownTenant) {
+			errorRender(w, r, http.StatusNotFound, fmt.Errorf("%w: %s", err, name))
+			return
+		}
+		if err != nil {
+			w.Header().Set("Retry-After", "1")
+			errorRender(w, r, http.StatusServiceUnavailable, err)
+			return
+		}
+		if t.inFlight != nil {
+			select {
+			case t.inFlight <- struct{}{}:
+				defer func() { <-t.inFlight }()
+			default:
+				rateLimitedRequests.WithLabelValues("tenant_in_flight", requestKind(r)).Inc()
+				w.Header().Set("Retry-After", "1")
+				errorRender(w, r, http.StatusServiceUnavailable, errors.New("too many concurrent requests for the tenant"))
+				return
+			}
+		}
+		// The selection is shared with the middlewares that already ran, so that the metrics are labeled with the tenant.
+		sel.t = t
+		httplog.LogEntrySetField(r.Context(), "tenant", name)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("tenant", name))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unaryInterceptor selects the tenant of gRPC calls with the tenant header in the metadata, and checks that the
+// caller may use it. It must run after the authentication interceptor.
+func (tr *tenantRouter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	name := defaultTenant
+	if values := md.Get(tr.cfg.TenantHeader); len(values) > 0 && values[0] != "" {
+		name = strings.ToLower(values[0])
+	}
+	if err := tr.checkAccess(ctx, name); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if name == defaultTenant {
+		return handler(ctx, req)
+	}
+	t, err := tr.tenant(ctx, name)
+	if errors.Is(err, errUnknownTenant) {
+		return nil, status.Errorf(codes.NotFound, "%v: %s", err, name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if t.inFlight != nil {
+		select {
+		case t.inFlight <- struct{}{}:
+			defer func() { <-t.inFlight }()
+		default:
+			return nil, status.Error(codes.ResourceExhausted, "too many concurrent requests for the tenant")
+		}
+	}
+	return handler(withTenant(ctx, t), req)
+}
+
+// repoFor returns the repository of the tenant of the request of ctx, or the repository of the main configuration if
+// the request did not select a tenant.
+func (m MusicDbOperation) repoFor(ctx context.Context) MusicRepository {
+	if t := tenantFrom(ctx); t != nil {
+		return t.repo
+	}
+	return m.repo
+}
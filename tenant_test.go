@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"shin5ok/simple-gorm-with-cloud-spanner/internal/pgfake"
+)
+
+func TestTenantRouterOpensTenantsConcurrently(t *testing.T) {
+	// The slow tenant accepts connections, but never answers the startup message of the client.
+	slow, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Close()
+	accepted := make(chan net.Conn, 10)
+	go func() {
+		for {
+			c, err := slow.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- c
+		}
+	}()
+	defer func() {
+		for len(accepted) > 0 {
+			(<-accepted).Close()
+		}
+	}()
+	fast, err := pgfake.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fast.Close()
+
+	cfg := defaultConfig()
+	cfg.ConnectTimeout = 500 * time.Millisecond
+	addr := slow.Addr().(*net.TCPAddr)
+	cfg.Tenants = map[string]TenantConfig{
+		"slow": {ConnString: fmt.Sprintf("host=%s port=%d user=test database=test sslmode=disable", addr.IP, addr.Port)},
+		"fast": {ConnString: fast.DSN()},
+	}
+	tr := newTenantRouter(cfg, zerolog.Nop())
+	defer tr.close()
+
+	// A request that gives up does not wait for the opening.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := tr.tenant(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	slowErr := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := tr.tenant(context.Background(), "slow")
+			slowErr <- err
+		}()
+	}
+	if _, err := tr.tenant(context.Background(), "fast"); err != nil {
+		t.Fatalf("got %v for the fast tenant while the slow tenant is opened", err)
+	}
+
+	// The requests of the slow tenant fail after the connect timeout.
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-slowErr:
+			if err == nil {
+				t.Error("got no error for the slow tenant")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the opening of the slow tenant did not time out")
+		}
+	}
+}
+
+func TestTenantRouterCheckAccess(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Tenants = map[string]TenantConfig{
+		"label1": {DatabaseName: "label1", Subjects: []string{"label1-ci"}},
+		"label2": {DatabaseName: "label2", Subjects: []string{"label2-ci"}},
+	}
+	tr := newTenantRouter(cfg, zerolog.Nop())
+	for _, tc := range []struct {
+		subject, tenant string
+		allowed         bool
+	}{
+		{"label1-ci", "label1", true},
+		{"label1-ci", "label2", false},
+		{"label1-ci", defaultTenant, false},
+		{"ci", defaultTenant, true},
+		{"ci", "label1", false},
+	} {
+		ctx := withIdentity(context.Background(), &Identity{Subject: tc.subject, Role: roleEditor, Method: "api_key"})
+		if err := tr.checkAccess(ctx, tc.tenant); (err == nil) != tc.allowed || err != nil && !errors.Is(err, errTenantNotAllowed) {
+			t.Errorf("%s using %s: got %v, want allowed %v", tc.subject, tc.tenant, err, tc.allowed)
+		}
+	}
+	if err := tr.checkAccess(withIdentity(context.Background(), anonymous), "label2"); err != nil {
+		t.Errorf("got %v without authentication", err)
+	}
+}
+
+func TestTenantsAreSelectedAfterAuthentication(t *testing.T) {
+	server, err := pgfake.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	cfg := defaultConfig()
+	cfg.AuthEnabled, cfg.APIKeys = true, []string{"ci:editor:ci-key", "label1-ci:editor:label1-key"}
+	cfg.Tenants = map[string]TenantConfig{"label1": {ConnString: server.DSN(), Subjects: []string{"label1-ci"}}}
+	tr := newTenantRouter(cfg, zerolog.Nop())
+	defer tr.close()
+	authn, err := newAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := MusicDbOperation{repo: newMemoryRepository(), cfg: cfg, state: &serverState{}, tenants: tr}
+	r, err := m.newRouter(authn, newRateLimiter(cfg), zerolog.Nop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened := func() int {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		return len(tr.tenants) + len(tr.opening)
+	}
+
+	// Known and unknown tenants get the same status until the caller may use the tenant.
+	for _, tc := range []struct {
+		path, key, tenant string
+		want              int
+	}{
+		{"/api/concerts", "", "label1", http.StatusUnauthorized},
+		{"/api/concerts", "", "unknown", http.StatusUnauthorized},
+		{"/api/concerts", "ci-key", "label1", http.StatusForbidden},
+		{"/api/concerts", "ci-key", "unknown", http.StatusForbidden},
+		{"/api/concerts", "label1-key", "unknown", http.StatusForbidden},
+		{"/healthz", "", "unknown", http.StatusOK},
+		{"/metrics", "", "label1", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.Header.Set("X-Tenant", tc.tenant)
+		if tc.key != "" {
+			req.Header.Set("X-API-Key", tc.key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s with key %q and tenant %s: got status %d, want %d", tc.path, tc.key, tc.tenant, w.Code, tc.want)
+		}
+	}
+	if got := opened(); got != 0 {
+		t.Fatalf("got %d tenants opened by rejected requests, want none", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/concerts", nil)
+	req.Header.Set("X-Tenant", "label1")
+	req.Header.Set("X-API-Key", "label1-key")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || opened() != 1 || len(statementsWith(server, `FROM "concerts"`)) != 1 {
+		t.Errorf("got status %d, %s, want the concerts of the tenant database", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,7 @@
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
@@ -0,0 +1,575 @@
+BEGIN;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+BEGIN;
+INSERT INTO "albums" ("id","created_at","updated_at","title","marketing_budget","release_date","cover_picture","singer_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
+BEGIN;
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42),($43,$44,$45,$46,$47,$48);
+INSERT INTO "tracks" ("id","created_at","updated_at","track_number","title","sample_rate") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24),($25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36);
+COMMIT;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+INSERT INTO "singers" ("id","created_at","updated_at","first_name","last_name","active") VALUES ($1,$2,$3,$4,$5,$6);
+COMMIT;
+SELECT * FROM "singers" WHERE "singers"."id" = $1 LIMIT 1;
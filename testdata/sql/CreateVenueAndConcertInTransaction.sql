@@ -0,0 +1,5 @@
+BEGIN;
+SELECT * FROM "singers" ORDER BY "singers"."id" LIMIT 1;
+INSERT INTO "venues" ("id","created_at","updated_at","name","description") VALUES ($1,$2,$3,$4,$5);
+INSERT INTO "concerts" ("id","created_at","updated_at","name","venue_id","singer_id","start_time","end_time") VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+COMMIT;
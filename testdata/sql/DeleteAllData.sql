@@ -0,0 +1,4 @@
+DELETE FROM concerts;
+DELETE FROM venues;
+DELETE FROM albums;
+DELETE FROM singers;
@@ -0,0 +1,4 @@
+BEGIN;
+SELECT * FROM "albums" ORDER BY "albums"."id" LIMIT 1;
+DELETE FROM "albums" WHERE "albums"."id" = $1;
+COMMIT;
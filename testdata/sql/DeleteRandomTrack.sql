@@ -0,0 +1,4 @@
+BEGIN;
+SELECT * FROM "tracks" ORDER BY "tracks"."id" LIMIT 1;
+DELETE FROM "tracks" WHERE ("tracks"."id","tracks"."track_number") IN (($1,$2));
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+SELECT * FROM "venues" WHERE "venues"."name" = $1 ORDER BY "venues"."id" LIMIT 1;
+COMMIT;
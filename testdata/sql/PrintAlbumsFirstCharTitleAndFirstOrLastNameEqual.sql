@@ -0,0 +1 @@
+SELECT "albums"."id","albums"."created_at","albums"."updated_at","albums"."title","albums"."marketing_budget","albums"."release_date","albums"."cover_picture","albums"."singer_id","Singer"."id" AS "Singer__id","Singer"."created_at" AS "Singer__created_at","Singer"."updated_at" AS "Singer__updated_at","Singer"."first_name" AS "Singer__first_name","Singer"."last_name" AS "Singer__last_name","Singer"."full_name" AS "Singer__full_name","Singer"."active" AS "Singer__active" FROM "albums" LEFT JOIN "singers" "Singer" ON "albums"."singer_id" = "Singer"."id" WHERE lower(substring(albums.title, 1, 1)) = lower(substring("Singer".first_name, 1, 1))or lower(substring(albums.title, 1, 1)) = lower(substring("Singer".last_name, 1, 1)) ORDER BY "Singer".last_name, "albums".release_date asc;
@@ -0,0 +1 @@
+SELECT * FROM "albums" WHERE release_date < $1 ORDER BY release_date asc;
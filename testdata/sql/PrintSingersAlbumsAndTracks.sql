@@ -0,0 +1 @@
+SELECT * FROM "singers" ORDER BY last_name;
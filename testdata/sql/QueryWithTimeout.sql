@@ -0,0 +1 @@
+SELECT * FROM "tracks" WHERE substring(title, 1, 1)='a';
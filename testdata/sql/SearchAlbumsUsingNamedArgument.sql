@@ -0,0 +1 @@
+SELECT * FROM "albums" WHERE title like $1 ORDER BY title;
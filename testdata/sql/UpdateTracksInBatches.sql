@@ -0,0 +1,3 @@
+BEGIN;
+SELECT * FROM "tracks" WHERE sample_rate > 44.1 ORDER BY "tracks"."id" LIMIT 20;
+COMMIT;
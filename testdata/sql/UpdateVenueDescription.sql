@@ -0,0 +1,4 @@
+BEGIN;
+SELECT * FROM "venues" WHERE name = $1;
+UPDATE "venues" SET "description"=$1,"updated_at"=$2 WHERE "id" = $3;
+COMMIT;
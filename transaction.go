@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// maxTransactionAttempts is the number of times that runTransaction executes a transaction that is aborted.
+const maxTransactionAttempts = 3
+
+// runTransaction executes fn in a transaction on db and records the outcome. Cloud Spanner can abort read/write
+// transactions, which PGAdapter reports with SQLSTATE 40001, so aborted transactions are retried. Transactions that
+// lose their connection before the commit are retried as well, as they have been rolled back. fn can therefore be
+// called more than once and must not have side effects outside the transaction.
+func runTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) (err error) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// The statements of all attempts are children of the transaction span.
+	ctx, span := tracer.Start(ctx, "gorm.transaction", trace.WithAttributes(requestIDKey.String(middleware.GetReqID(ctx))))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	db = db.WithContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		span.SetAttributes(attribute.Int("db.transaction.attempts", attempt))
+		tx := db.Begin()
+		if tx.Error != nil {
+			return tx.Error
+		}
+		err := runTransactionOnce(tx, fn)
+		if err == nil {
+			dbTransactions.WithLabelValues("commit", tenantName(ctx)).Inc()
+			return nil
+		}
+		dbTransactions.WithLabelValues("rollback", tenantName(ctx)).Inc()
+		if !isRetryableTransactionError(err) || attempt == maxTransactionAttempts {
+			return err
+		}
+		span.AddEvent("retry", trace.WithAttributes(attribute.String("error", err.Error())))
+		dbTransactionRetries.Inc()
+		if !sleepContext(ctx, backoff(attempt, 10*time.Millisecond, time.Second)) {
+			return err
+		}
+	}
+}
+
+// commitError is returned by runTransactionOnce if the commit failed.
+type commitError struct {
+	err error
+}
+
+func (e *commitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *commitError) Unwrap() error {
+	return e.err
+}
+
+// runTransactionOnce executes fn in tx and commits tx, or rolls it back if fn fails.
+func runTransactionOnce(tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	finished := false
+	defer func() {
+		// Also roll back if fn panics.
+		if !finished {
+			tx.Rollback()
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	finished = true
+	if err := tx.Commit().Error; err != nil {
+		return &commitError{err: err}
+	}
+	return nil
+}
+
+// isRetryableTransactionError returns true if the transaction that failed with err can safely be executed again.
+// A commit that fails because of a connection failure might have been applied, so it is not retried.
+func isRetryableTransactionError(err error) bool {
+	var commitErr *commitError
+	return isAbortedError(err) || (isConnectionError(err) && !errors.As(err, &commitErr))
+}
+
+// isAbortedError returns true if err is a serialization failure, which is how PGAdapter reports that Cloud Spanner
+// aborted the transaction.
+func isAbortedError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "40001"
+}